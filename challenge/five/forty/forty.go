@@ -0,0 +1,41 @@
+package forty
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	msg = []byte("Hastad's broadcast attack")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	var captures []rsa.Capture
+	for i := 0; i < 3; i++ {
+		k, err := rsa.GenerateKey(1024, 3)
+		if err != nil {
+			return err
+		}
+		ct, err := k.EncryptBytes(msg)
+		if err != nil {
+			return err
+		}
+		captures = append(captures, rsa.Capture{Ct: ct, Key: &k.PublicKey})
+	}
+
+	pt, err := rsa.Broadcast(captures)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recovered: %s\n", aurora.Cyan(string(pt)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
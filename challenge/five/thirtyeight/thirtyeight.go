@@ -0,0 +1,64 @@
+package thirtyeight
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/srp"
+	"github.com/logrusorgru/aurora"
+	"net"
+)
+
+const (
+	email    = "alice@example.com"
+	password = "sunshine"
+)
+
+var (
+	wordlist = []string{
+		"123456", "password", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+		"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+		"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+		"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+		"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+		"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	}
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	l, err := srp.Listen()
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	found := make(chan string, 1)
+	errs := make(chan error, 1)
+	go srp.Serve(l, func(conn net.Conn) error {
+		cracked, err := srp.CrackSimple(conn, srp.DefaultParams, wordlist)
+		found <- cracked
+		errs <- err
+		return err
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := srp.SimpleLogin(conn, srp.DefaultParams, email, password); err != srp.LoginFailedErr {
+		return err
+	}
+	cracked := <-found
+	if err := <-errs; err != nil {
+		return err
+	}
+	fmt.Printf("Cracked password of %s: %s\n", email, aurora.Cyan(cracked))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
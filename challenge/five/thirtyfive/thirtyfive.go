@@ -0,0 +1,42 @@
+package thirtyfive
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dh"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	msgs = [][]byte{
+		[]byte("Hi Bob, it's Alice"),
+		[]byte("Nobody can read this, right?"),
+	}
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	attacks := []struct {
+		name string
+		mitm *dh.Mitm
+	}{
+		{"g = 1", dh.MaliciousGOne()},
+		{"g = p", dh.MaliciousGP()},
+		{"g = p - 1", dh.MaliciousGPMinusOne()},
+	}
+	for _, a := range attacks {
+		if err := dh.Run(dh.Modp1536, msgs, a.mitm); err != nil {
+			return err
+		}
+		for _, pt := range a.mitm.Recovered {
+			fmt.Printf("Intercepted message with %s: %s\n", a.name, aurora.Cyan(string(pt)))
+		}
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
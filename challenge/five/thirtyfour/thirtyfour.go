@@ -0,0 +1,33 @@
+package thirtyfour
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dh"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	msgs = [][]byte{
+		[]byte("Hi Bob, it's Alice"),
+		[]byte("Nobody can read this, right?"),
+	}
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	mitm := dh.KeyFixing()
+	if err := dh.Run(dh.Modp1536, msgs, mitm); err != nil {
+		return err
+	}
+	for _, pt := range mitm.Recovered {
+		fmt.Printf("Intercepted message: %s\n", aurora.Cyan(string(pt)))
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
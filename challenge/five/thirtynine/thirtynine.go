@@ -0,0 +1,32 @@
+package thirtynine
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	k, err := rsa.GenerateKey(1024, 3)
+	if err != nil {
+		return err
+	}
+	ct, err := k.EncryptBytes([]byte("Textbook RSA works"))
+	if err != nil {
+		return err
+	}
+	pt, err := k.DecryptBytes(ct)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Decrypted: %s\n", aurora.Cyan(string(rsa.BytesToInt(pt).Bytes())))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,47 @@
+package thirtyseven
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/srp"
+	"github.com/logrusorgru/aurora"
+	"net"
+)
+
+const (
+	email = "alice@example.com"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	s := srp.NewServer(srp.DefaultParams)
+	s.Register(email, fmt.Sprintf("%x", crypto.RandomKey(16)))
+
+	l, err := srp.Listen()
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	go srp.Serve(l, s.Handle)
+
+	for _, multiple := range []int64{0, 1, 2} {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return err
+		}
+		err = srp.ZeroKeyLogin(conn, srp.DefaultParams, email, multiple)
+		conn.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged in as %s with A = %d * N\n", aurora.Cyan(email), multiple)
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,44 @@
+package thirtysix
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/srp"
+	"github.com/logrusorgru/aurora"
+	"net"
+)
+
+const (
+	email    = "alice@example.com"
+	password = "correct horse battery staple"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	s := srp.NewServer(srp.DefaultParams)
+	s.Register(email, password)
+
+	l, err := srp.Listen()
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	go srp.Serve(l, s.Handle)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := srp.Login(conn, srp.DefaultParams, email, password); err != nil {
+		return err
+	}
+	fmt.Printf("Logged in as %s\n", aurora.Cyan(email))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
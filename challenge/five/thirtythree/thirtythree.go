@@ -0,0 +1,35 @@
+package thirtythree
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dh"
+	"github.com/logrusorgru/aurora"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	for _, g := range []dh.Group{dh.Toy, dh.Modp1536} {
+		a, err := dh.GenerateKey(g)
+		if err != nil {
+			return err
+		}
+		b, err := dh.GenerateKey(g)
+		if err != nil {
+			return err
+		}
+
+		sa, sb := a.Shared(b.Y), b.Shared(a.Y)
+		if sa.Cmp(sb) != 0 {
+			return challenge.WrongOutputErr(sa, sb)
+		}
+		fmt.Printf("Shared key in group %s: %x\n", g.Name, aurora.Cyan(dh.Sha256Key(sa)))
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,39 @@
+package thirty
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/md4"
+	"github.com/logrusorgru/aurora"
+	"math/rand"
+)
+
+var (
+	msg       = []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	extension = []byte(";admin=true")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	key := crypto.RandomKey(1 + rand.Intn(32))
+	mac, verify := crypto.SecretPrefix(md4.New).Oracles(key, crypto.ConstantTimeCompare)
+
+	original, err := mac(msg)
+	if err != nil {
+		return err
+	}
+
+	f, err := crypto.LengthExtensionAttack(md4.Extender{}, verify, msg, original, extension, 0, 64)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Forged MAC %x for key length %d\n", aurora.Cyan(f.Mac), aurora.Cyan(f.KeyLen))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,53 @@
+package thirtytwo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"github.com/kdhageman/go-cryptopals/crypto/timing"
+	"github.com/logrusorgru/aurora"
+	"time"
+)
+
+const (
+	file  = "foo"
+	delay = 5 * time.Millisecond
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	key := crypto.RandomKey(16)
+	srv, err := timing.NewServer(key, sha1.Size, delay)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	opts := timing.AttackOpts{
+		Samples:   5,
+		MaxRounds: 5,
+		Progress: func(sig []byte) {
+			fmt.Printf("\rRecovered signature: %x", sig)
+		},
+	}
+	res, err := timing.RecoverSignature(timing.HttpOracle(srv.Client(), srv.URL), file, sha1.Size, opts)
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	expected := crypto.Hmac(sha1.New)(key, []byte(file))
+	if !bytes.Equal(expected, res.Signature) {
+		return challenge.WrongOutputErr(expected, res.Signature)
+	}
+	fmt.Printf("Found signature %x in %d queries\n", aurora.Cyan(res.Signature), aurora.Cyan(res.Queries))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,45 @@
+package twentyeight
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"github.com/logrusorgru/aurora"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	mac, verify := crypto.SecretPrefix(sha1.New).Oracles(crypto.RandomKey(16), crypto.ConstantTimeCompare)
+	msg := []byte("some kind of somewhat long plain text!")
+
+	tag, err := mac(msg)
+	if err != nil {
+		return err
+	}
+	ok, err := verify(msg, tag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return challenge.WrongOutputErr(true, ok)
+	}
+
+	tampered := append([]byte{}, msg...)
+	tampered[0] ^= 0x01
+	ok, err = verify(tampered, tag)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return challenge.WrongOutputErr(false, ok)
+	}
+	fmt.Printf("MAC %x only verifies for the original message\n", aurora.Cyan(tag))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
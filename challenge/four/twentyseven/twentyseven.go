@@ -0,0 +1,39 @@
+package twentyseven
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/logrusorgru/aurora"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	key := crypto.RandomKey(16)
+	enc, dec := crypto.KeyAsIvOracles(key)
+
+	found, err := crypto.RecoverKeyAsIv(enc, dec)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(key, found) {
+		return challenge.WrongOutputErr(key, found)
+	}
+	fmt.Printf("Recovered key: %x\n", aurora.Cyan(found))
+
+	warnings := crypto.KeyAsIvWarnings()
+	if len(warnings) == 0 {
+		return challenge.WrongOutputErr("key used as IV", "no call sites flagged")
+	}
+	for w, count := range warnings {
+		fmt.Printf("%s: key used as IV at %s:%d (%d calls)\n", aurora.Yellow("warning"), w.File, w.Line, count)
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,60 @@
+package twentysix
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/challenge/two/sixteen"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/logrusorgru/aurora"
+)
+
+func oracle() (crypto.Oracle, crypto.Oracle, error) {
+	ctr, err := crypto.NewCtr(nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptor := func(userdata []byte) ([]byte, error) {
+		d := sixteen.FromUserData(string(userdata))
+		return ctr.Encrypt([]byte(d.String()))
+	}
+	decryptor := func(ct []byte) ([]byte, error) {
+		return ctr.Decrypt(ct)
+	}
+	return encryptor, decryptor, nil
+}
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	enc, dec, err := oracle()
+	if err != nil {
+		return err
+	}
+
+	layout := crypto.Layout{
+		PrefixLen: len("comment1=cooking%20MCs;userdata="),
+		Forbidden: []byte(";="),
+	}
+	craftedCt, _, err := crypto.CtrBitflipAttack(enc, layout, []byte(";admin=true"))
+	if err != nil {
+		return err
+	}
+
+	pt, err := dec(craftedCt)
+	if err != nil {
+		return err
+	}
+	d, err := sixteen.FromBytes(pt)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User is admin: %t\n", aurora.Cyan(d.IsAdmin()))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
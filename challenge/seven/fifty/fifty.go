@@ -0,0 +1,35 @@
+package fifty
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	key    = []byte("YELLOW SUBMARINE")
+	target = []byte("alert('MZA who was that?');\n")
+	prefix = []byte("alert('Ayo, the Wu is back!');//")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	// line breaks would end the comment that hides the rest of the snippet
+	forged, err := crypto.CbcMacCollision(key, prefix, target, ' ', []byte("\r\n"))
+	if err != nil {
+		return err
+	}
+	hash, err := crypto.CbcMac(key, make([]byte, 16), forged)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Snippet %q hashes to %s\n", forged, aurora.Cyan(fmt.Sprintf("%x", hash)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
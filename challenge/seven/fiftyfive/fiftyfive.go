@@ -0,0 +1,37 @@
+package fiftyfive
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/md4"
+	"github.com/logrusorgru/aurora"
+	"math/rand"
+	"time"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	start := time.Now()
+	res := md4.Collision(rand.New(rand.NewSource(time.Now().UnixNano())))
+	fmt.Printf("Found collision after %d attempts in %s\n", res.Attempts, time.Since(start))
+	fmt.Printf("M  = %x\nM' = %x\n", res.A, res.B)
+	fmt.Printf("MD4 = %s\n", aurora.Cyan(fmt.Sprintf("%x", md4.Sum(res.A))))
+
+	held := 0
+	checks := md4.Explain(res.A)
+	for _, check := range checks {
+		if check.Holds {
+			held++
+		} else {
+			fmt.Println(check)
+		}
+	}
+	fmt.Printf("%d of %d conditions hold\n", held, len(checks))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
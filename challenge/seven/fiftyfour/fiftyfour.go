@@ -0,0 +1,48 @@
+package fiftyfour
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/mdhash"
+	"github.com/logrusorgru/aurora"
+)
+
+const (
+	k = 8
+)
+
+var (
+	// the prefix is padded to whole blocks
+	prefix = []byte("Final score: Ajax 3 - Feyenoord 1")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	h, err := mdhash.New(3, nil)
+	if err != nil {
+		return err
+	}
+	for len(prefix)%mdhash.BlockSize != 0 {
+		prefix = append(prefix, ' ')
+	}
+
+	d := mdhash.NewDiamond(h, k)
+	prediction, err := d.Prediction(uint64(len(prefix)))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Predicted %s after building the diamond with %d calls\n", aurora.Cyan(fmt.Sprintf("%x", prediction)), d.Calls)
+
+	res, err := d.Herd(prefix)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Herded %q into %s with %d calls\n", prefix, aurora.Cyan(fmt.Sprintf("%x", h.Sum(res.Message))), res.Calls)
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
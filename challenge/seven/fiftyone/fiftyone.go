@@ -0,0 +1,45 @@
+package fiftyone
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/crime"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	sessionId = []byte("TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	ciphers := []struct {
+		name   string
+		cipher crime.Cipher
+	}{
+		{"CTR", crime.Ctr},
+		{"CBC", crime.Cbc},
+	}
+	for _, ci := range ciphers {
+		res, err := crime.Recover(crime.NewOracle(sessionId, ci.cipher), []byte("sessionid="), crime.AttackOpts{
+			Alphabet:  []byte(crime.Base64),
+			MaxLength: 64,
+			MaxFiller: 32,
+			Repeat:    4,
+			Progress: func(sessionId []byte) {
+				fmt.Printf("\r%s: %s", ci.name, sessionId)
+			},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\rRecovered session id with %s after %d queries: %s\n", ci.name, res.Queries, aurora.Cyan(string(res.SessionId)))
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
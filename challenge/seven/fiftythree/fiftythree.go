@@ -0,0 +1,37 @@
+package fiftythree
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/mdhash"
+	"github.com/logrusorgru/aurora"
+)
+
+const (
+	k = 10
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	h, err := mdhash.New(3, nil)
+	if err != nil {
+		return err
+	}
+	msg := make([]byte, mdhash.BlockSize<<k)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	res, err := mdhash.SecondPreimage(h, msg, k)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Second preimage with digest %s after %d calls\n", aurora.Cyan(fmt.Sprintf("%x", h.Sum(res.Message))), res.Calls)
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,37 @@
+package fiftytwo
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/mdhash"
+	"github.com/logrusorgru/aurora"
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	f, err := mdhash.New(2, nil)
+	if err != nil {
+		return err
+	}
+	g, err := mdhash.New(3, []byte{0xde, 0xad, 0xbe})
+	if err != nil {
+		return err
+	}
+
+	m := f.Multicollision(f.Iv(), 4)
+	fmt.Printf("Found %d colliding messages of f with %d calls\n", m.Len(), m.Calls)
+
+	res, err := mdhash.CascadeCollision(f, g)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Collision of f || g: %s (%d calls to f, %d calls to g)\n",
+		aurora.Cyan(fmt.Sprintf("%x", mdhash.CascadeSum(f, g, res.A))), res.FCalls, res.GCalls)
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,74 @@
+package fortynine
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
+)
+
+const (
+	victim   = 1
+	attacker = 2
+)
+
+var (
+	TagRejectedErr = errors.New("server rejected the forged tag")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	key := crypto.RandomKey(16)
+
+	// the attacker controls the IV sent along with the message
+	mac, verify := crypto.CbcMacOracles(key, crypto.SentIv)
+	own := []byte(fmt.Sprintf("from=%d&to=%d&amount=1000000", attacker, attacker))
+	tag, err := mac(own)
+	if err != nil {
+		return err
+	}
+	forged := []byte(fmt.Sprintf("from=%d&to=%d&amount=1000000", victim, attacker))
+	forgedTag, err := crypto.ForgeIv(own, tag, forged)
+	if err != nil {
+		return err
+	}
+	if ok, err := verify(forged, forgedTag); err != nil {
+		return err
+	} else if !ok {
+		return TagRejectedErr
+	}
+	fmt.Printf("Accepted: %s\n", aurora.Cyan(string(forged)))
+
+	// with a fixed IV, a captured message of the victim is extended with a transaction of the attacker
+	mac, verify = crypto.CbcMacOracles(key, crypto.FixedIv)
+	captured := []byte(fmt.Sprintf("from=%d&tx_list=3:500;4:200", victim))
+	capturedTag, err := mac(captured)
+	if err != nil {
+		return err
+	}
+	own = []byte(fmt.Sprintf("from=%d&tx_list=0:0;%d:1000000", attacker, attacker))
+	ownTag, err := mac(own)
+	if err != nil {
+		return err
+	}
+	forged, forgedTag, err = crypto.CbcMacExtend(captured, capturedTag, own, ownTag)
+	if err != nil {
+		return err
+	}
+	if ok, err := verify(forged, forgedTag); err != nil {
+		return err
+	} else if !ok {
+		return TagRejectedErr
+	}
+	txs := bytes.Split(forged[bytes.Index(forged, []byte("tx_list="))+len("tx_list="):], []byte(";"))
+	fmt.Printf("Accepted transactions from %d: %q\n", victim, txs)
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,24 @@
+package fortyeight
+
+import (
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/challenge/six/fortyseven"
+)
+
+const (
+	bits = 768
+)
+
+var (
+	msg = []byte("kick it, CC")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	return fortyseven.Attack(bits, msg)
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,57 @@
+package fortyfive
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dsa"
+	"github.com/logrusorgru/aurora"
+	"math/big"
+)
+
+var (
+	msgs = [][]byte{
+		[]byte("Hello, world"),
+		[]byte("Goodbye, world"),
+	}
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	zero, err := dsa.GenerateKey(dsa.DefaultParams.WithG(big.NewInt(0)))
+	if err != nil {
+		return err
+	}
+	sig := dsa.ZeroGeneratorSignature()
+	for _, msg := range msgs {
+		if err := dsa.Insecure.Verify(&zero.PublicKey, dsa.Hash(msg), sig); err != nil {
+			return err
+		}
+		fmt.Printf("g = 0 signature verifies for %s\n", aurora.Cyan(string(msg)))
+	}
+
+	k, err := dsa.GenerateKey(dsa.DefaultParams)
+	if err != nil {
+		return err
+	}
+	pub := &dsa.PublicKey{
+		Params: dsa.DefaultParams.WithG(new(big.Int).Add(dsa.DefaultParams.P, big.NewInt(1))),
+		Y:      k.Y,
+	}
+	magic, err := dsa.MagicSignature(pub, big.NewInt(1))
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if err := dsa.Insecure.Verify(pub, dsa.Hash(msg), magic); err != nil {
+			return err
+		}
+		fmt.Printf("g = p + 1 signature verifies for %s\n", aurora.Cyan(string(msg)))
+	}
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,61 @@
+package fortyfour
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dsa"
+	"github.com/logrusorgru/aurora"
+	"math/big"
+)
+
+var (
+	msgs = []string{
+		"Listen for me, you better listen for me now. ",
+		"Pure black people mon is all I mon know. ",
+		"Yeah me shoes a an tear up an' now me toes is a show a ",
+		"Where me a don't care, I'll go mad if me find the one who steal my shoes. ",
+		"Yeah me a get stuck in a nuh a get out. ",
+		"Sun shining, weather sweet, make you want to move your dancing feet. ",
+	}
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	k, err := dsa.GenerateKey(dsa.DefaultParams)
+	if err != nil {
+		return err
+	}
+
+	// the signer draws its nonces from a pool that is far too small
+	var pool []*big.Int
+	for i := 0; i < 4; i++ {
+		nonce, err := rand.Int(rand.Reader, k.Q)
+		if err != nil {
+			return err
+		}
+		pool = append(pool, nonce.Add(nonce, big.NewInt(1)))
+	}
+	var signed []dsa.SignedHash
+	for i, msg := range msgs {
+		h := dsa.Hash([]byte(msg))
+		sig, err := dsa.Secure.SignNonce(k, h, pool[i%len(pool)])
+		if err != nil {
+			return err
+		}
+		signed = append(signed, dsa.SignedHash{H: h, Sig: sig})
+	}
+
+	recovered, err := dsa.RecoverFromRepeatedNonce(&k.PublicKey, signed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recovered private key: %s\n", aurora.Cyan(recovered.X.Text(16)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
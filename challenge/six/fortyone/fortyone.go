@@ -0,0 +1,43 @@
+package fortyone
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	msg = []byte(`{"time": 1356304276, "social": "555-55-5555"}`)
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	k, err := rsa.GenerateKey(1024, 65537)
+	if err != nil {
+		return err
+	}
+	decrypt := rsa.UnpaddedOracle(k)
+
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		return err
+	}
+	// the legitimate user decrypts the message first, after which the server refuses to decrypt it again
+	if _, err := decrypt(ct); err != nil {
+		return err
+	}
+
+	pt, err := rsa.RecoverUnpadded(&k.PublicKey, ct, decrypt)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recovered: %s\n", aurora.Cyan(string(pt)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,54 @@
+package fortyseven
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+)
+
+const (
+	bits = 256
+)
+
+var (
+	msg = []byte("kick it, CC")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	return Attack(bits, msg)
+}
+
+// Attack encrypts the padded message under a fresh key of the given size and decrypts it with Bleichenbacher's attack
+func Attack(bits int, msg []byte) error {
+	k, err := rsa.GenerateKey(bits, 3)
+	if err != nil {
+		return err
+	}
+	em, err := rsa.PadPkcs1v15(msg, k.Size())
+	if err != nil {
+		return err
+	}
+	ct, err := k.EncryptBytes(em)
+	if err != nil {
+		return err
+	}
+
+	res, err := rsa.Bleichenbacher(&k.PublicKey, ct, rsa.NewPaddingOracle(k, rsa.Loose))
+	if err != nil {
+		return err
+	}
+	pt, err := rsa.UnpadPkcs1v15(res.Plaintext)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Recovered after %d queries: %s\n", res.Queries, aurora.Cyan(string(pt)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,43 @@
+package fortysix
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+	"os"
+)
+
+const (
+	input = "VGhhdCdzIHdoeSBJIGZvdW5kIHlvdSBkb24ndCBwbGF5IGFyb3VuZCB3aXRoIHRoZSBGdW5reSBDb2xkIE1lZGluYQ=="
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	msg, err := base64.StdEncoding.DecodeString(input)
+	if err != nil {
+		return err
+	}
+	k, err := rsa.GenerateKey(1024, 65537)
+	if err != nil {
+		return err
+	}
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		return err
+	}
+
+	pt, err := rsa.ParityAttack(&k.PublicKey, ct, rsa.NewParityOracle(k), rsa.Hollywood(os.Stdout))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nRecovered: %s\n", aurora.Cyan(string(pt)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
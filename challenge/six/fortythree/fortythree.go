@@ -0,0 +1,53 @@
+package fortythree
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/dsa"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"github.com/logrusorgru/aurora"
+	"math/big"
+)
+
+const (
+	y = "84ad4719d044495496a3201c8ff484feb45b962e7302e56a392aee4abab3e4bdebf2955b4736012f21a08084056b19bcd7f" +
+		"ee56048e004e44984e2f411788efdc837a0d2e5abb7b555039fd243ac01f0fb2ed1dec568280ce678e931868d23eb095fde9d37" +
+		"79191b8c0299d6e07bbb283e6633451e535c45513b2d33c99ea17"
+	r = "548099063082341131477253921760299949438196259240"
+	s = "857042759984254168557880549501802188789837994940"
+
+	fingerprint = "0954edd5e0afe5542a4adf012611a91912a3ec16"
+)
+
+var (
+	msg = []byte("For those that envy a MC it can be hazardous to your health\n" +
+		"So be friendly, a matter of life and death, just like a etch-a-sketch\n")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	Y, _ := new(big.Int).SetString(y, 16)
+	R, _ := new(big.Int).SetString(r, 10)
+	S, _ := new(big.Int).SetString(s, 10)
+	pub := &dsa.PublicKey{Params: dsa.DefaultParams, Y: Y}
+	sh := dsa.SignedHash{H: dsa.Hash(msg), Sig: dsa.Signature{R: R, S: S}}
+
+	k, tried, err := dsa.RecoverFromNonceRange(pub, sh, 0, 1<<16)
+	if err != nil {
+		return err
+	}
+	h := sha1.New()
+	h.Write([]byte(k.X.Text(16)))
+	actual := fmt.Sprintf("%x", h.Sum(nil))
+	if actual != fingerprint {
+		return challenge.WrongOutputErr(fingerprint, actual)
+	}
+	fmt.Printf("Recovered private key after %d nonces, fingerprint: %s\n", tried, aurora.Cyan(actual))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
@@ -0,0 +1,39 @@
+package fortytwo
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/logrusorgru/aurora"
+)
+
+const (
+	padding = 4
+)
+
+var (
+	msg = []byte("hi mom")
+)
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	k, err := rsa.GenerateKey(1024, 3)
+	if err != nil {
+		return err
+	}
+	sig, err := rsa.ForgeCubeRoot(&k.PublicKey, rsa.Sha1, msg, padding)
+	if err != nil {
+		return err
+	}
+	if err := rsa.LaxVerifier.Verify(&k.PublicKey, rsa.Sha1, msg, sig); err != nil {
+		return err
+	}
+	fmt.Printf("Forged signature for %q: %s\n", msg, aurora.Cyan(fmt.Sprintf("%x", sig)))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
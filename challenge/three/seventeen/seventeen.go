@@ -63,14 +63,7 @@ func (d Decrypt) DecryptBlock(ct []byte, prev []byte) ([]byte, error) {
 	return pt, nil
 }
 
-func oracle() (Encrypt, Decrypt, error) {
-	pts, err := file.ReadBase64Lines("challenge/three/seventeen/input.txt")
-	if err != nil {
-		return nil, nil, err
-	}
-	key := crypto.RandomKey(aes.BlockSize)
-	iv := crypto.RandomKey(aes.BlockSize)
-
+func Oracle(pts [][]byte, key []byte, iv []byte) (Encrypt, Decrypt) {
 	enc := func() ([]byte, []byte, error) {
 		pt := randomPt(pts)
 		ct, err := crypto.EncryptCbc(pt, key, iv)
@@ -86,13 +79,17 @@ func oracle() (Encrypt, Decrypt, error) {
 		}
 		return true, nil
 	}
-	return enc, dec, nil
+	return enc, dec
 }
 
 type ch struct{}
 
 func (c *ch) Solve() error {
-	enc, dec, err := oracle()
+	pts, err := file.ReadBase64Lines("challenge/three/seventeen/input.txt")
+	if err != nil {
+		return err
+	}
+	enc, dec := Oracle(pts, crypto.RandomKey(aes.BlockSize), crypto.RandomKey(aes.BlockSize))
 	var pt []byte
 
 	ct, iv, err := enc()
@@ -0,0 +1,57 @@
+package twentyfour
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/mersenne"
+	"github.com/logrusorgru/aurora"
+	"math/rand"
+	"time"
+)
+
+var (
+	known = bytes.Repeat([]byte("A"), 14)
+)
+
+func oracle(seed uint16) crypto.Oracle {
+	c := mersenne.NewStreamCipher(seed)
+	return func(pt []byte) ([]byte, error) {
+		prefix := crypto.RandomKey(5 + rand.Intn(20))
+		return c.Encrypt(append(prefix, pt...))
+	}
+}
+
+type ch struct{}
+
+func (c *ch) Solve() error {
+	seed := uint16(rand.Intn(1 << 16))
+	ct, err := oracle(seed)(known)
+	if err != nil {
+		return err
+	}
+
+	found, err := mersenne.RecoverStreamSeed(ct, known)
+	if err != nil {
+		return err
+	}
+	if found != seed {
+		return challenge.WrongOutputErr(seed, found)
+	}
+	fmt.Printf("Found stream cipher seed: %d\n", aurora.Cyan(found))
+
+	now := time.Now()
+	token := mersenne.TokenFromTime(now.Add(-time.Duration(rand.Intn(600))*time.Second), 16)
+	ts, ok := mersenne.DetectTimeSeededToken(token, mersenne.TimeRange(now.Add(-time.Hour), now))
+	if !ok {
+		return challenge.WrongOutputErr(true, ok)
+	}
+	fmt.Printf("Token %x was seeded with timestamp %d\n", token, aurora.Cyan(ts))
+
+	return nil
+}
+
+func New() challenge.Challenge {
+	return &ch{}
+}
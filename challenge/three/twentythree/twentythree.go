@@ -7,19 +7,6 @@ import (
 	"github.com/logrusorgru/aurora"
 )
 
-func untemper(n int32) uint32 {
-	y := uint64(n) & 0xffffffff
-	y ^= y >> 18
-	y ^= (y << 15) & 0xefc60000
-	for i := 0; i < 7; i++ {
-		y ^= (y << 7) & 0x9d2c5680
-	}
-	for i := 0; i < 3; i++ {
-		y ^= y >> 11
-	}
-	return uint32(y)
-}
-
 type ch struct{}
 
 func (c *ch) Solve() error {
@@ -27,32 +14,34 @@ func (c *ch) Solve() error {
 	mt := mersenne.New()
 	mt.Seed(seed)
 
-	expected := make([]int32, 624)
-	state := make([]uint32, 624)
+	observed := make([]int32, 624)
 	for i := 0; i < 624; i++ {
 		v, err := mt.Rand()
 		if err != nil {
 			return err
 		}
-		expected[i] = v
-		state[i] = untemper(v)
+		observed[i] = v
+	}
+
+	other, err := mersenne.Clone(observed)
+	if err != nil {
+		return err
 	}
 
-	other := mersenne.FromSlice(state)
-	actual := make([]int32, 624)
 	for i := 0; i < 624; i++ {
-		v, err := other.Rand()
+		expected, err := mt.Rand()
 		if err != nil {
 			return err
 		}
-		actual[i] = v
-	}
-
-	for i := range expected {
-		if expected[i] != actual[i] {
-			fmt.Printf("Expected %11d, but got %11d\n", aurora.Cyan(expected[i]), aurora.Cyan(actual[i]))
+		actual, err := other.Rand()
+		if err != nil {
+			return err
+		}
+		if expected != actual {
+			return challenge.WrongOutputErr(expected, actual)
 		}
 	}
+	fmt.Printf("Predicted %d future outputs\n", aurora.Cyan(624))
 
 	return nil
 }
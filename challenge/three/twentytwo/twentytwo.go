@@ -12,41 +12,52 @@ import (
 
 type ch struct{}
 
-func rng() (int32, error) {
+func rng() ([]int32, error) {
 	mt := mersenne.New()
 	seed := time.Now().Unix() + 40 + rand.Int63n(60000)
 	fmt.Printf("Actual seed: %d\n", aurora.Cyan(seed))
 	mt.Seed(int(seed))
-	return mt.Rand()
+
+	// discard a few outputs, so the observed ones are not the first
+	skip := rand.Intn(10)
+	var res []int32
+	for i := 0; i < skip+2; i++ {
+		v, err := mt.Rand()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res[skip:], nil
 }
 
 func (c *ch) Solve() error {
-	target, err := rng()
+	observed, err := rng()
 	if err != nil {
 		return err
 	}
 
-	found := false
-	mt := mersenne.New()
-	lower := time.Now().Unix() + 35
-	for i := 0; i < 80000; i++ {
-		seed := lower + int64(i)
-		mt.Seed(int(seed))
-		actual, err := mt.Rand()
-		if err != nil {
-			return err
-		}
-		if actual == target {
-			fmt.Printf("Found seed: %d\n", aurora.Cyan(seed))
-			found = true
-			break
-		}
+	now := time.Now()
+	r := mersenne.TimeRange(now, now.Add(24*time.Hour))
+	opts := mersenne.CrackOpts{
+		MaxOffset: 10,
+		Progress: func(checked, total uint64) {
+			fmt.Printf("\rChecked %d/%d seeds", checked, total)
+		},
 	}
-
-	if !found {
+	matches, err := mersenne.CrackSeed(observed, r, opts)
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
 		return errors.New("failed to find seed")
 	}
 
+	for _, m := range matches {
+		fmt.Printf("Found seed: %d (offset %d)\n", aurora.Cyan(m.Seed), aurora.Cyan(m.Offset))
+	}
+
 	return nil
 }
 
@@ -52,10 +52,7 @@ func FromBytes(b []byte) (User, error) {
 	return d, nil
 }
 
-func oracle() (crypto.Oracle, crypto.Oracle) {
-	key := crypto.RandomKey(16)
-	iv := crypto.RandomKey(16)
-
+func Oracle(key []byte, iv []byte) (crypto.Oracle, crypto.Oracle) {
 	encryptor := func(userdata []byte) ([]byte, error) {
 		d := FromUserData(string(userdata))
 		pt := []byte(d.String())
@@ -70,23 +67,17 @@ func oracle() (crypto.Oracle, crypto.Oracle) {
 type ch struct{}
 
 func (c *ch) Solve() error {
-	enc, dec := oracle()
-	ct, err := enc([]byte(" admin true"))
+	enc, dec := Oracle(crypto.RandomKey(16), crypto.RandomKey(16))
+	layout := crypto.Layout{
+		PrefixLen: len("comment1=cooking%20MCs;userdata="),
+		BlockSize: 16,
+		Forbidden: []byte(";="),
+	}
+	craftedCt, plan, err := crypto.CbcBitflipAttack(enc, layout, []byte(";admin=true"))
 	if err != nil {
 		return err
 	}
-
-	semicolon, _ := crypto.Xor([]byte(" "), []byte(";"))
-	equals, _ := crypto.Xor([]byte(" "), []byte("="))
-
-	dSemicolon, _ := crypto.Xor(semicolon, []byte{ct[16]})
-	dEquals, _ := crypto.Xor(equals, []byte{ct[22]})
-
-	craftedCt := ct[:16]
-	craftedCt = append(craftedCt, dSemicolon...)
-	craftedCt = append(craftedCt, ct[17:22]...)
-	craftedCt = append(craftedCt, dEquals...)
-	craftedCt = append(craftedCt, ct[23:]...)
+	fmt.Printf("Scrambled plain text blocks: %v\n", aurora.Cyan(plan.Scrambled))
 
 	pt, err := dec(craftedCt)
 	if err != nil {
@@ -1,7 +1,6 @@
 package thirteen
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/kdhageman/go-cryptopals/challenge"
 	"github.com/kdhageman/go-cryptopals/crypto"
@@ -10,9 +9,9 @@ import (
 	"strings"
 )
 
-type profile map[string]string
+type Profile map[string]string
 
-func decode(s string) (profile, error) {
+func Decode(s string) (Profile, error) {
 	v, err := url.ParseQuery(s)
 	if err != nil {
 		return nil, err
@@ -24,15 +23,7 @@ func decode(s string) (profile, error) {
 	return p, nil
 }
 
-func decrypt(ct []byte, key []byte) (profile, error) {
-	pt, err := crypto.DecryptEcb(ct, key)
-	if err != nil {
-		return nil, err
-	}
-	return decode(string(pt))
-}
-
-func (v profile) encode() string {
+func (v Profile) encode() string {
 	var els []string
 	for _, el := range []string{"email", "uid", "role"} {
 		els = append(els, fmt.Sprintf("%s=%s", el, v[el]))
@@ -40,12 +31,12 @@ func (v profile) encode() string {
 	return strings.Join(els, "&")
 }
 
-func (v profile) encrypt(key []byte) ([]byte, error) {
+func (v Profile) encrypt(key []byte) ([]byte, error) {
 	pt := []byte(v.encode())
 	return crypto.EncryptEcb(pt, key)
 }
 
-func profileFor(email string) profile {
+func profileFor(email string) Profile {
 	r := strings.NewReplacer("&", "", "=", "")
 	email = r.Replace(email)
 	return map[string]string{
@@ -55,16 +46,13 @@ func profileFor(email string) profile {
 	}
 }
 
-type Decryptor func([]byte) (profile, error)
-
-func oracle() (crypto.Oracle, Decryptor) {
-	key := crypto.RandomKey(16)
+func Oracle(key []byte) (crypto.Oracle, crypto.Oracle) {
 	e := func(pt []byte) ([]byte, error) {
 		p := profileFor(string(pt))
 		return p.encrypt(key)
 	}
-	d := func(ct []byte) (profile, error) {
-		return decrypt(ct, key)
+	d := func(ct []byte) ([]byte, error) {
+		return crypto.DecryptEcb(ct, key)
 	}
 	return e, d
 }
@@ -72,27 +60,36 @@ func oracle() (crypto.Oracle, Decryptor) {
 type ch struct{}
 
 func (c *ch) Solve() error {
-	e, d := oracle()
-
-	padding := bytes.Repeat([]byte{0xff}, 10)
-
-	padding = append(padding, []byte("admin")...)
-	padding = append(padding, bytes.Repeat([]byte{0x11}, 11)...)
+	e, d := Oracle(crypto.RandomKey(16))
 
-	adminCt, err := e(padding)
+	tmpl := crypto.Template{
+		Prefix:    []byte("email="),
+		Suffix:    []byte("&uid=10&role=user"),
+		BlockSize: 16,
+		Forbidden: []byte("&="),
+	}
+	desired := Profile{
+		"email": "foooo@bar.com",
+		"uid":   "10",
+		"role":  "admin",
+	}
+	tamperedCt, err := crypto.CutAndPaste(e, d, tmpl, []byte(desired.encode()))
 	if err != nil {
 		return err
 	}
 
-	padding = bytes.Repeat([]byte{0xff}, 13)
-	baseCt, err := e(padding)
+	pt, err := d(tamperedCt)
+	if err != nil {
+		return err
+	}
+	pt, err = crypto.RemovePkcs7(pt, 16)
+	if err != nil {
+		return err
+	}
+	p, err := Decode(string(pt))
 	if err != nil {
 		return err
 	}
-
-	tamperedCt := append(baseCt[:32], adminCt[16:32]...)
-
-	p, err := d(tamperedCt)
 	fmt.Printf("Role of profile: %s\n", aurora.Cyan(p["role"]))
 
 	return nil
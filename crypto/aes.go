@@ -81,6 +81,7 @@ func DecryptEcb(ct []byte, key []byte) ([]byte, error) {
 }
 
 func EncryptCbc(pt []byte, key []byte, initialIv []byte) ([]byte, error) {
+	checkKeyAsIv(key, initialIv)
 	c, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -102,6 +103,7 @@ func EncryptCbc(pt []byte, key []byte, initialIv []byte) ([]byte, error) {
 }
 
 func DecryptCbc(ct []byte, key []byte, initialIv []byte) ([]byte, error) {
+	checkKeyAsIv(key, initialIv)
 	c, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
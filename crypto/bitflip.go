@@ -0,0 +1,201 @@
+package crypto
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+)
+
+var (
+	NoPlaceholderErr = errors.New("failed to find a placeholder byte that is not forbidden")
+	UnplannableErr   = errors.New("failed to find a filler layout for the bytes to flip")
+)
+
+// Layout describes where user input ends up in the plain text of an encryption oracle
+type Layout struct {
+	// PrefixLen is the number of plain text bytes preceding the user input
+	PrefixLen int
+	BlockSize int
+	// Forbidden are the bytes the oracle removes from or escapes in the user input
+	Forbidden []byte
+}
+
+// Flip XORs Mask into the cipher text byte at Index
+type Flip struct {
+	Index int
+	Mask  byte
+}
+
+type BitflipPlan struct {
+	// Input is the user input to submit to the encryption oracle
+	Input []byte
+	Flips []Flip
+	// Scrambled are the indices of the plain text blocks that decrypt to garbage, in increasing order
+	Scrambled []int
+}
+
+func (p BitflipPlan) Apply(ct []byte) []byte {
+	res := make([]byte, len(ct))
+	copy(res, ct)
+	for _, f := range p.Flips {
+		res[f.Index] ^= f.Mask
+	}
+	return res
+}
+
+// placeholders replaces the forbidden bytes of inject by allowed bytes that differ in a single bit
+func placeholders(inject []byte, forbidden []byte) ([]byte, error) {
+	res := make([]byte, len(inject))
+	copy(res, inject)
+	for i, b := range inject {
+		if bytes.IndexByte(forbidden, b) < 0 {
+			continue
+		}
+		found := false
+		for bit := uint(0); bit < 8; bit++ {
+			c := b ^ (1 << bit)
+			if bytes.IndexByte(forbidden, c) < 0 {
+				res[i] = c
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, NoPlaceholderErr
+		}
+	}
+	return res, nil
+}
+
+// PlanCbcBitflip plans how to inject a string containing forbidden bytes through a CBC encryption oracle.
+// The forbidden bytes are submitted as placeholders and corrected by flipping bits in the preceding cipher text block,
+// which scrambles that block. Every block of the injected string that contains placeholders therefore needs a block
+// of filler in front of it. Filler is prepended to the input, such that the first of these blocks follows a filler
+// block, and a block of filler is inserted before every further block with placeholders. If all placeholders fall in
+// a single block, only one block is scrambled and the injected string stays contiguous; otherwise it is split by the
+// inserted blocks, and removing the scrambled blocks from the plain text leaves it intact.
+func PlanCbcBitflip(layout Layout, inject []byte) (BitflipPlan, error) {
+	bsize := layout.BlockSize
+	placeholder, err := placeholders(inject, layout.Forbidden)
+	if err != nil {
+		return BitflipPlan{}, err
+	}
+	if bytes.Equal(inject, placeholder) {
+		return BitflipPlan{Input: placeholder}, nil
+	}
+
+	var best *BitflipPlan
+	for pad := 0; pad < 3*bsize; pad++ {
+		plan, ok := layoutCbcBitflip(layout, inject, placeholder, pad)
+		if !ok {
+			continue
+		}
+		if best == nil || len(plan.Scrambled) < len(best.Scrambled) {
+			best = &plan
+		}
+	}
+	if best == nil {
+		return BitflipPlan{}, UnplannableErr
+	}
+	return *best, nil
+}
+
+// layoutCbcBitflip lays out the input after pad bytes of filler, block by block. It reports false if a block with
+// placeholders starts halfway a block that is not filler.
+func layoutCbcBitflip(layout Layout, inject, placeholder []byte, pad int) (BitflipPlan, bool) {
+	bsize := layout.BlockSize
+	plan := BitflipPlan{
+		Input: bytes.Repeat([]byte("A"), pad),
+	}
+	// filler[b] reports whether plain text block b consists of filler only
+	filler := map[int]bool{}
+	for b := (layout.PrefixLen + bsize - 1) / bsize; (b+1)*bsize <= layout.PrefixLen+pad; b++ {
+		filler[b] = true
+	}
+
+	pos := layout.PrefixLen + pad
+	for i := 0; i < len(placeholder); {
+		b := pos / bsize
+		end := i + (b+1)*bsize - pos
+		if end > len(placeholder) {
+			end = len(placeholder)
+		}
+		if bytes.Equal(inject[i:end], placeholder[i:end]) {
+			plan.Input = append(plan.Input, placeholder[i:end]...)
+			pos += end - i
+			i = end
+			continue
+		}
+
+		if !filler[b-1] {
+			if pos%bsize != 0 {
+				return BitflipPlan{}, false
+			}
+			// sacrifice a block of filler to correct the next block
+			plan.Input = append(plan.Input, bytes.Repeat([]byte("A"), bsize)...)
+			filler[b] = true
+			pos += bsize
+			continue
+		}
+		plan.Scrambled = append(plan.Scrambled, b-1)
+		for j := i; j < end; j++ {
+			if inject[j] != placeholder[j] {
+				plan.Flips = append(plan.Flips, Flip{
+					Index: pos + j - i - bsize,
+					Mask:  inject[j] ^ placeholder[j],
+				})
+			}
+		}
+		plan.Input = append(plan.Input, placeholder[i:end]...)
+		pos += end - i
+		i = end
+	}
+	return plan, true
+}
+
+// CbcBitflipAttack returns a cipher text that decrypts to a plain text containing inject at the planned position
+func CbcBitflipAttack(encrypt Oracle, layout Layout, inject []byte) ([]byte, BitflipPlan, error) {
+	plan, err := PlanCbcBitflip(layout, inject)
+	if err != nil {
+		return nil, plan, err
+	}
+	ct, err := encrypt(plan.Input)
+	if err != nil {
+		return nil, plan, err
+	}
+	return plan.Apply(ct), plan, nil
+}
+
+// PlanCtrBitflip plans how to inject a string containing forbidden bytes through a CTR encryption oracle.
+// As every plain text byte is XORed with its own keystream byte, the placeholders are corrected by flipping
+// the cipher text bytes at the same position, without scrambling anything.
+func PlanCtrBitflip(layout Layout, inject []byte) (BitflipPlan, error) {
+	placeholder, err := placeholders(inject, layout.Forbidden)
+	if err != nil {
+		return BitflipPlan{}, err
+	}
+	plan := BitflipPlan{
+		Input: placeholder,
+	}
+	for i := range inject {
+		if inject[i] != placeholder[i] {
+			plan.Flips = append(plan.Flips, Flip{
+				Index: layout.PrefixLen + i,
+				Mask:  inject[i] ^ placeholder[i],
+			})
+		}
+	}
+	return plan, nil
+}
+
+// CtrBitflipAttack returns a cipher text that decrypts to a plain text containing inject right after the prefix
+func CtrBitflipAttack(encrypt Oracle, layout Layout, inject []byte) ([]byte, BitflipPlan, error) {
+	plan, err := PlanCtrBitflip(layout, inject)
+	if err != nil {
+		return nil, plan, err
+	}
+	ct, err := encrypt(plan.Input)
+	if err != nil {
+		return nil, plan, err
+	}
+	return plan.Apply(ct), plan, nil
+}
@@ -0,0 +1,162 @@
+package crypto
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func cbcOracles(prefix, suffix string) (Oracle, Oracle) {
+	key := RandomKey(16)
+	iv := RandomKey(16)
+	r := strings.NewReplacer(";", "", "=", "")
+
+	enc := func(input []byte) ([]byte, error) {
+		pt := prefix + r.Replace(string(input)) + suffix
+		return EncryptCbc([]byte(pt), key, iv)
+	}
+	dec := func(ct []byte) ([]byte, error) {
+		return DecryptCbc(ct, key, iv)
+	}
+	return enc, dec
+}
+
+func TestCbcBitflipAttack(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		inject    string
+		scrambled []int
+	}{
+		{
+			name:      "Aligned prefix",
+			prefix:    "comment1=cooking%20MCs;userdata=",
+			inject:    ";admin=true",
+			scrambled: []int{2},
+		},
+		{
+			name:      "Unaligned prefix",
+			prefix:    "comment1=cooking",
+			inject:    ";admin=true",
+			scrambled: []int{1},
+		},
+		{
+			name:      "Injected string spans blocks",
+			prefix:    "userdata=",
+			inject:    ";admin=true&note:a%20very%20long%20note%20spanning%20blocks",
+			scrambled: []int{1},
+		},
+		{
+			name:      "Forbidden bytes span blocks",
+			prefix:    "comment1=cooking%20MCs;userdata=",
+			inject:    ";admin=true;note=a%20long%20note;role=admin;",
+			scrambled: []int{2, 4, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, dec := cbcOracles(tt.prefix, ";comment2=%20like%20a%20pound%20of%20bacon")
+			layout := Layout{
+				PrefixLen: len(tt.prefix),
+				BlockSize: 16,
+				Forbidden: []byte(";="),
+			}
+
+			ct, plan, err := CbcBitflipAttack(enc, layout, []byte(tt.inject))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(plan.Scrambled, tt.scrambled) {
+				t.Fatalf("Expected blocks %v to be scrambled, but got %v", tt.scrambled, plan.Scrambled)
+			}
+			pt, err := dec(ct)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			// the injected string is intact once the scrambled blocks are removed
+			var intact []byte
+			for i, block := range InBlocks(pt, 16) {
+				if len(plan.Scrambled) > 0 && plan.Scrambled[0] == i {
+					plan.Scrambled = plan.Scrambled[1:]
+					continue
+				}
+				intact = append(intact, block...)
+			}
+			if !bytes.Contains(intact, []byte(tt.inject)) {
+				t.Fatalf("Expected plain text %q to contain %q", intact, tt.inject)
+			}
+			if !bytes.HasPrefix(pt, []byte(tt.prefix)) {
+				t.Fatalf("Expected plain text %q to start with intact prefix %q", pt, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestPlanCbcBitflip(t *testing.T) {
+	layout := Layout{
+		PrefixLen: 0,
+		BlockSize: 16,
+		Forbidden: []byte(";="),
+	}
+	// the placeholders cannot share a block, so a filler block is sacrificed for the second one
+	plan, err := PlanCbcBitflip(layout, []byte(";aaaaaaaaaaaaaaaaaaaa="))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []int{0, 2}; !reflect.DeepEqual(plan.Scrambled, expected) {
+		t.Fatalf("Expected blocks %v to be scrambled, but got %v", expected, plan.Scrambled)
+	}
+	if len(plan.Flips) != 2 {
+		t.Fatalf("Expected %d flips, but got %d", 2, len(plan.Flips))
+	}
+
+	layout.Forbidden = []byte{0x3b, 0x3a, 0x39, 0x3f, 0x33, 0x2b, 0x1b, 0x7b, 0xbb}
+	if _, err := PlanCbcBitflip(layout, []byte(";")); err != NoPlaceholderErr {
+		t.Fatalf("Expected error %s, but got %s", NoPlaceholderErr, err)
+	}
+
+	plan, err = PlanCbcBitflip(layout, []byte("nothing forbidden"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(plan.Scrambled) != 0 || len(plan.Flips) != 0 {
+		t.Fatalf("Expected no flips, but got %v", plan)
+	}
+}
+
+func TestCtrBitflipAttack(t *testing.T) {
+	prefix := "comment1=cooking%20MCs;userdata="
+	ctr, err := NewCtr(nil, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	r := strings.NewReplacer(";", "", "=", "")
+	enc := func(input []byte) ([]byte, error) {
+		return ctr.Encrypt([]byte(prefix + r.Replace(string(input)) + ";comment2=%20like%20a%20pound%20of%20bacon"))
+	}
+
+	layout := Layout{
+		PrefixLen: len(prefix),
+		Forbidden: []byte(";="),
+	}
+	inject := []byte(";admin=true;")
+	ct, plan, err := CtrBitflipAttack(enc, layout, inject)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(plan.Scrambled) != 0 {
+		t.Fatalf("Expected no scrambled blocks, but got %v", plan.Scrambled)
+	}
+	if len(plan.Flips) != 3 {
+		t.Fatalf("Expected %d flips, but got %d", 3, len(plan.Flips))
+	}
+
+	pt, err := ctr.Decrypt(ct)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := prefix + string(inject); !bytes.HasPrefix(pt, []byte(expected)) {
+		t.Fatalf("Expected plain text %q to start with %q", pt, expected)
+	}
+}
@@ -0,0 +1,134 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+var (
+	InvalidTagErr       = errors.New("CBC-MAC tag has an invalid size")
+	NotFirstBlockErr    = errors.New("forged message differs from the original beyond the first block")
+	NoCollisionFoundErr = errors.New("failed to find a collision block without forbidden bytes")
+	ShortMessageErr     = errors.New("message must contain at least one block")
+)
+
+// IvHandling determines where the IV of a CBC-MAC comes from
+type IvHandling int
+
+const (
+	// FixedIv uses an all-zero IV on both sides, and tags only contain the MAC
+	FixedIv = IvHandling(iota)
+	// SentIv lets the signer pick a random IV, which is sent as IV || MAC in the tag
+	SentIv
+)
+
+// CbcMac returns the last cipher text block of the message encrypted with CBC
+func CbcMac(key, iv, msg []byte) ([]byte, error) {
+	ct, err := EncryptCbc(msg, key, iv)
+	if err != nil {
+		return nil, err
+	}
+	return ct[len(ct)-aes.BlockSize:], nil
+}
+
+// CbcMacOracles returns oracles that generate and verify CBC-MAC tags under the given key
+func CbcMacOracles(key []byte, h IvHandling) (MacOracle, VerifyOracle) {
+	mac := func(msg []byte) ([]byte, error) {
+		iv := make([]byte, aes.BlockSize)
+		if h == SentIv {
+			iv = RandomKey(aes.BlockSize)
+		}
+		tag, err := CbcMac(key, iv, msg)
+		if err != nil {
+			return nil, err
+		}
+		if h == SentIv {
+			return append(iv, tag...), nil
+		}
+		return tag, nil
+	}
+	verify := func(msg []byte, tag []byte) (bool, error) {
+		iv := make([]byte, aes.BlockSize)
+		if h == SentIv {
+			if len(tag) != 2*aes.BlockSize {
+				return false, InvalidTagErr
+			}
+			iv, tag = tag[:aes.BlockSize], tag[aes.BlockSize:]
+		}
+		expected, err := CbcMac(key, iv, msg)
+		if err != nil {
+			return false, err
+		}
+		return ConstantTimeCompare(expected, tag), nil
+	}
+	return mac, verify
+}
+
+// ForgeIv turns a tag of type SentIv for msg into a tag for forged, which may only differ from msg in the first
+// block. Since the IV is XORed into the first block, flipping the same bits in the IV keeps the MAC intact.
+func ForgeIv(msg, tag, forged []byte) ([]byte, error) {
+	if len(tag) != 2*aes.BlockSize {
+		return nil, InvalidTagErr
+	}
+	if len(msg) != len(forged) || len(msg) < aes.BlockSize || !bytes.Equal(msg[aes.BlockSize:], forged[aes.BlockSize:]) {
+		return nil, NotFirstBlockErr
+	}
+	iv := Xor(tag[:aes.BlockSize], Xor(msg[:aes.BlockSize], forged[:aes.BlockSize]))
+	return append(iv, tag[aes.BlockSize:]...), nil
+}
+
+// CbcMacExtend combines two messages with their fixed IV tags into a message that has the second tag.
+// The first block of the second message is XORed with the first tag, so that the chain continues as if the second
+// message was MACed from a zero IV; that block is garbled in the result.
+func CbcMacExtend(msg1, tag1, msg2, tag2 []byte) ([]byte, []byte, error) {
+	if len(tag1) != aes.BlockSize || len(tag2) != aes.BlockSize {
+		return nil, nil, InvalidTagErr
+	}
+	if len(msg2) < aes.BlockSize {
+		return nil, nil, ShortMessageErr
+	}
+	res := PadPkcs7(append([]byte{}, msg1...), aes.BlockSize)
+	res = append(res, Xor(msg2[:aes.BlockSize], tag1)...)
+	res = append(res, msg2[aes.BlockSize:]...)
+	return res, tag2, nil
+}
+
+// CbcMacCollision finds a message that starts with prefix and has the same zero IV CBC-MAC as target.
+// The prefix is padded with filler and a counter up to a block boundary, followed by a block that glues the chain
+// to the second block of the target. The counter is incremented until the glue block contains no forbidden bytes.
+func CbcMacCollision(key, prefix, target []byte, filler byte, forbidden []byte) ([]byte, error) {
+	if len(target) < aes.BlockSize {
+		return nil, ShortMessageErr
+	}
+	iv := make([]byte, aes.BlockSize)
+	for i := 0; i < 1<<16; i++ {
+		body := append(append([]byte{}, prefix...), strconv.Itoa(i)...)
+		for len(body)%aes.BlockSize != 0 {
+			body = append(body, filler)
+		}
+		// the cipher text block at the end of the body is the chaining state
+		ct, err := EncryptCbc(body, key, iv)
+		if err != nil {
+			return nil, err
+		}
+		state := ct[len(body)-aes.BlockSize : len(body)]
+		glue := Xor(state, target[:aes.BlockSize])
+		if containsAnyByte(glue, forbidden) {
+			continue
+		}
+		res := append(body, glue...)
+		return append(res, target[aes.BlockSize:]...), nil
+	}
+	return nil, NoCollisionFoundErr
+}
+
+func containsAnyByte(b, set []byte) bool {
+	for _, c := range set {
+		if bytes.IndexByte(b, c) >= 0 {
+			return true
+		}
+	}
+	return false
+}
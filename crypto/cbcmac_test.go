@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCbcMac(t *testing.T) {
+	// the JavaScript snippet hash from cryptopals challenge 50
+	tag, err := CbcMac([]byte("YELLOW SUBMARINE"), make([]byte, 16), []byte("alert('MZA who was that?');\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := "296b8d7cb78a243dda4d0a61d33bbdd1"; hex.EncodeToString(tag) != expected {
+		t.Fatalf("Expected %s, but got %x", expected, tag)
+	}
+}
+
+func TestCbcMacOracles(t *testing.T) {
+	for _, h := range []IvHandling{FixedIv, SentIv} {
+		mac, verify := CbcMacOracles(RandomKey(16), h)
+		msg := []byte("from=1&to=2&amount=100")
+		tag, err := mac(msg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if ok, err := verify(msg, tag); err != nil || !ok {
+			t.Fatalf("Expected tag to verify, but got %t (%v)", ok, err)
+		}
+		if ok, _ := verify([]byte("from=1&to=2&amount=999"), tag); ok {
+			t.Fatalf("Expected tag not to verify for another message")
+		}
+	}
+}
+
+func TestForgeIv(t *testing.T) {
+	mac, verify := CbcMacOracles(RandomKey(16), SentIv)
+	msg := []byte("from=2&to=2&amount=1000000")
+	forged := []byte("from=1&to=2&amount=1000000")
+	tag, err := mac(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	forgedTag, err := ForgeIv(msg, tag, forged)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ok, err := verify(forged, forgedTag); err != nil || !ok {
+		t.Fatalf("Expected forged tag to verify, but got %t (%v)", ok, err)
+	}
+
+	if _, err := ForgeIv(msg, tag, []byte("from=2&to=2&amount=9000000")); err != NotFirstBlockErr {
+		t.Fatalf("Expected error %s, but got %v", NotFirstBlockErr, err)
+	}
+}
+
+func TestCbcMacExtend(t *testing.T) {
+	mac, verify := CbcMacOracles(RandomKey(16), FixedIv)
+	victim := []byte("from=1&tx_list=3:500;4:200")
+	own := []byte("from=2&tx_list=0:0;2:1000000")
+	victimTag, err := mac(victim)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	ownTag, err := mac(own)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	forged, tag, err := CbcMacExtend(victim, victimTag, own, ownTag)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.HasPrefix(forged, victim) || !bytes.HasSuffix(forged, []byte(";2:1000000")) {
+		t.Fatalf("Expected forged message to extend the victim's message, but got %q", forged)
+	}
+	if ok, err := verify(forged, tag); err != nil || !ok {
+		t.Fatalf("Expected forged tag to verify, but got %t (%v)", ok, err)
+	}
+
+	// padding must not write into spare capacity of the caller's message
+	buf := make([]byte, len(victim), 2*len(victim))
+	copy(buf, victim)
+	if _, _, err := CbcMacExtend(buf, victimTag, own, ownTag); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if spare := buf[len(buf):cap(buf)]; !bytes.Equal(spare, make([]byte, len(spare))) {
+		t.Fatalf("Expected spare capacity to be untouched, but got %x", spare)
+	}
+}
+
+func TestCbcMacCollision(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	target := []byte("alert('MZA who was that?');\n")
+	prefix := []byte("alert('Ayo, the Wu is back!');//")
+	forbidden := []byte("\r\n")
+
+	forged, err := CbcMacCollision(key, prefix, target, ' ', forbidden)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.HasPrefix(forged, prefix) {
+		t.Fatalf("Expected forged message to start with %q, but got %q", prefix, forged)
+	}
+	if bytes.IndexAny(forged[:len(forged)-1], "\r\n") >= 0 {
+		t.Fatalf("Expected no line breaks before the end, but got %q", forged)
+	}
+	expected, _ := CbcMac(key, make([]byte, 16), target)
+	actual, err := CbcMac(key, make([]byte, 16), forged)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected MAC %x, but got %x", expected, actual)
+	}
+}
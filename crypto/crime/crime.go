@@ -0,0 +1,219 @@
+// Package crime implements the compression ratio side-channel attack against requests that are compressed before
+// they are encrypted.
+package crime
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"math/rand"
+)
+
+const (
+	// Base64 is the alphabet of session ids
+	Base64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+	// terminator ends the session id in the request
+	terminator = '\r'
+)
+
+var (
+	AmbiguousErr = errors.New("failed to single out the next byte of the session id")
+)
+
+// Cipher is the encryption applied to the compressed request
+type Cipher int
+
+const (
+	Ctr = Cipher(iota)
+	Cbc
+)
+
+// Format returns the HTTP request that carries the session id and the body
+func Format(sessionId, body []byte) []byte {
+	return []byte(fmt.Sprintf("POST / HTTP/1.1\r\n"+
+		"Host: hapless.com\r\n"+
+		"Cookie: sessionid=%s\r\n"+
+		"Content-Length: %d\r\n"+
+		"\r\n"+
+		"%s", sessionId, len(body), body))
+}
+
+// Compress deflates the data
+func Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Oracle returns the length of the encrypted, compressed request with the attacker controlled body
+type Oracle func(body []byte) (int, error)
+
+// NewOracle returns an oracle that encrypts every request under a fresh key with the given cipher
+func NewOracle(sessionId []byte, c Cipher) Oracle {
+	return func(body []byte) (int, error) {
+		compressed, err := Compress(Format(sessionId, body))
+		if err != nil {
+			return 0, err
+		}
+		key := crypto.RandomKey(aes.BlockSize)
+		var ct []byte
+		switch c {
+		case Cbc:
+			ct, err = crypto.EncryptCbc(compressed, key, crypto.RandomKey(aes.BlockSize))
+		default:
+			var ctr crypto.Ctr
+			ctr, err = crypto.NewCtr(key, rand.Uint64())
+			if err != nil {
+				return 0, err
+			}
+			ct, err = ctr.Encrypt(compressed)
+		}
+		if err != nil {
+			return 0, err
+		}
+		return len(ct), nil
+	}
+}
+
+type AttackOpts struct {
+	// Alphabet contains the bytes the session id consists of
+	Alphabet []byte
+	// MaxLength is the maximum length of the session id
+	MaxLength int
+	// MaxFiller is the maximum number of incompressible bytes put in front of the guesses to align the compressed
+	// request with a block boundary, which is required when the cipher pads to whole blocks
+	MaxFiller int
+	// Repeat is the number of times each guess is repeated in the body. Short bodies do not compress well enough for
+	// deflate to prefer Huffman coding over storing the request uncompressed, which hides the compression ratio.
+	Repeat int
+	// Progress is called with the session id recovered so far after every byte
+	Progress func(sessionId []byte)
+}
+
+type Result struct {
+	SessionId []byte
+	Queries   int
+}
+
+type attack struct {
+	oracle  Oracle
+	opts    AttackOpts
+	filler  []byte
+	queries int
+}
+
+func (a *attack) query(filler int, guess []byte) (int, error) {
+	body := append([]byte{}, a.filler[:filler]...)
+	for i := 0; i < a.opts.Repeat || i == 0; i++ {
+		body = append(body, guess...)
+	}
+	a.queries++
+	return a.oracle(body)
+}
+
+// best returns the guesses that result in the shortest request
+func (a *attack) best(filler int, guesses [][]byte) ([][]byte, error) {
+	min := -1
+	var res [][]byte
+	for _, g := range guesses {
+		l, err := a.query(filler, g)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case min < 0 || l < min:
+			min = l
+			res = [][]byte{g}
+		case l == min:
+			res = append(res, g)
+		}
+	}
+	return res, nil
+}
+
+// next returns the byte that follows the known prefix
+func (a *attack) next(known []byte) (byte, error) {
+	candidates := append(append([]byte{}, a.opts.Alphabet...), terminator)
+	var guesses [][]byte
+	for _, c := range candidates {
+		guesses = append(guesses, append(append([]byte{}, known...), c))
+	}
+
+	for filler := 0; filler <= a.opts.MaxFiller; filler++ {
+		best, err := a.best(filler, guesses)
+		if err != nil {
+			return 0, err
+		}
+		if len(best) == 1 {
+			return best[0][len(known)], nil
+		}
+		if len(best) == len(guesses) {
+			// the length does not depend on the guess at this filler size
+			continue
+		}
+
+		// break ties by guessing the byte after the candidates as well
+		var pairs [][]byte
+		for _, g := range best {
+			for _, c := range candidates {
+				pairs = append(pairs, append(append([]byte{}, g...), c))
+			}
+		}
+		best, err = a.best(filler, pairs)
+		if err != nil {
+			return 0, err
+		}
+		unique := true
+		for _, g := range best {
+			unique = unique && g[len(known)] == best[0][len(known)]
+		}
+		if unique {
+			return best[0][len(known)], nil
+		}
+	}
+	return 0, AmbiguousErr
+}
+
+// Recover recovers the session id from the oracle, one byte at a time. Guesses that repeat the session id compress
+// better than others, which shows in the length of the encrypted request.
+func Recover(oracle Oracle, known []byte, opts AttackOpts) (Result, error) {
+	a := &attack{
+		oracle: oracle,
+		opts:   opts,
+		filler: make([]byte, opts.MaxFiller),
+	}
+	// filler bytes that neither appear in the request nor repeat, so that they do not compress
+	r := rand.New(rand.NewSource(1))
+	for i := range a.filler {
+		a.filler[i] = byte(0x80 + r.Intn(0x80))
+	}
+
+	var sessionId []byte
+	for len(sessionId) < opts.MaxLength {
+		b, err := a.next(append(append([]byte{}, known...), sessionId...))
+		if err != nil {
+			return Result{sessionId, a.queries}, err
+		}
+		if b == terminator {
+			break
+		}
+		sessionId = append(sessionId, b)
+		if opts.Progress != nil {
+			opts.Progress(sessionId)
+		}
+	}
+	return Result{sessionId, a.queries}, nil
+}
@@ -0,0 +1,59 @@
+package crime
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	sessionId = []byte("TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=")
+)
+
+func TestNewOracle(t *testing.T) {
+	for _, c := range []Cipher{Ctr, Cbc} {
+		oracle := NewOracle(sessionId, c)
+		compressed, err := Compress(Format(sessionId, []byte("body")))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		l, err := oracle([]byte("body"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if c == Ctr && l != len(compressed) {
+			t.Fatalf("Expected length %d, but got %d", len(compressed), l)
+		}
+		if c == Cbc && (l%16 != 0 || l <= len(compressed)) {
+			t.Fatalf("Expected padded length, but got %d", l)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name   string
+		cipher Cipher
+	}{
+		{"CTR", Ctr},
+		{"CBC", Cbc},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Recover(NewOracle(sessionId, tt.cipher), []byte("sessionid="), AttackOpts{
+				Alphabet:  []byte(Base64),
+				MaxLength: 64,
+				MaxFiller: 32,
+				Repeat:    4,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error after recovering %q: %s", res.SessionId, err)
+			}
+			if !bytes.Equal(sessionId, res.SessionId) {
+				t.Fatalf("Expected %s, but got %s", sessionId, res.SessionId)
+			}
+			if res.Queries == 0 {
+				t.Fatalf("Expected queries to be counted")
+			}
+		})
+	}
+}
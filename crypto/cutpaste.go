@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+)
+
+var (
+	NoCutFoundErr      = errors.New("failed to find an input that produces the desired block")
+	ForgeryRejectedErr = errors.New("forged cipher text does not decrypt to the desired plain text")
+)
+
+// Template describes the plain text an ECB encryption oracle produces: Prefix || input || Suffix
+type Template struct {
+	Prefix    []byte
+	Suffix    []byte
+	BlockSize int
+	// Forbidden are the bytes the oracle removes from or escapes in the input
+	Forbidden []byte
+	// Filler is the byte used for input that does not matter, defaulting to 'A'
+	Filler byte
+}
+
+// Cut is an input to the encryption oracle and the index of the cipher text block to cut from its output
+type Cut struct {
+	Input []byte
+	Block int
+}
+
+// planBlock finds an input for which block k of the padded plain text equals the desired block
+func (t Template) planBlock(desired []byte) (Cut, bool) {
+	bsize := t.BlockSize
+	filler := t.Filler
+	if filler == 0 {
+		filler = 'A'
+	}
+
+	maxLen := len(t.Prefix) + 3*bsize
+	for l := 0; l <= maxLen; l++ {
+		input := bytes.Repeat([]byte{filler}, l)
+		pt := append(append(append([]byte{}, t.Prefix...), input...), t.Suffix...)
+		pt = PadPkcs7(pt, bsize)
+
+		for k := 0; k < len(pt)/bsize; k++ {
+			candidate := make([]byte, l)
+			copy(candidate, input)
+			feasible := true
+			for i := 0; i < bsize; i++ {
+				pos := k*bsize + i
+				if pos >= len(t.Prefix) && pos < len(t.Prefix)+l {
+					// the byte comes from the input, so it can be chosen freely unless forbidden
+					if bytes.IndexByte(t.Forbidden, desired[i]) >= 0 {
+						feasible = false
+						break
+					}
+					candidate[pos-len(t.Prefix)] = desired[i]
+				} else if pt[pos] != desired[i] {
+					feasible = false
+					break
+				}
+			}
+			if feasible {
+				return Cut{candidate, k}, true
+			}
+		}
+	}
+	return Cut{}, false
+}
+
+// PlanCutAndPaste returns, for every block of the padded desired plain text, the input and cipher text block that produce it
+func PlanCutAndPaste(t Template, desired []byte) ([]Cut, error) {
+	var res []Cut
+	for _, block := range InBlocks(PadPkcs7(append([]byte{}, desired...), t.BlockSize), t.BlockSize) {
+		cut, ok := t.planBlock(block)
+		if !ok {
+			return nil, NoCutFoundErr
+		}
+		res = append(res, cut)
+	}
+	return res, nil
+}
+
+// ForgeCutAndPaste forges an ECB cipher text of the desired plain text by combining blocks of cipher texts of chosen inputs
+func ForgeCutAndPaste(encrypt Oracle, t Template, desired []byte) ([]byte, error) {
+	cuts, err := PlanCutAndPaste(t, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	cts := map[string][]byte{}
+	var forged []byte
+	for _, cut := range cuts {
+		ct, ok := cts[string(cut.Input)]
+		if !ok {
+			ct, err = encrypt(cut.Input)
+			if err != nil {
+				return nil, err
+			}
+			cts[string(cut.Input)] = ct
+		}
+		forged = append(forged, ct[cut.Block*t.BlockSize:(cut.Block+1)*t.BlockSize]...)
+	}
+	return forged, nil
+}
+
+// CutAndPaste forges an ECB cipher text of the desired plain text and verifies the forgery with the decryption oracle
+func CutAndPaste(encrypt, decrypt Oracle, t Template, desired []byte) ([]byte, error) {
+	forged, err := ForgeCutAndPaste(encrypt, t, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err := decrypt(forged)
+	if err != nil {
+		return nil, err
+	}
+	padded := PadPkcs7(append([]byte{}, desired...), t.BlockSize)
+	if !bytes.Equal(pt, desired) && !bytes.Equal(pt, padded) {
+		return nil, ForgeryRejectedErr
+	}
+	return forged, nil
+}
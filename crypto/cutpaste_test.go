@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func profileOracles(key []byte) (Oracle, Oracle) {
+	r := strings.NewReplacer("&", "", "=", "")
+	enc := func(input []byte) ([]byte, error) {
+		pt := "email=" + r.Replace(string(input)) + "&uid=10&role=user"
+		return EncryptEcb([]byte(pt), key)
+	}
+	dec := func(ct []byte) ([]byte, error) {
+		return DecryptEcb(ct, key)
+	}
+	return enc, dec
+}
+
+func TestCutAndPaste(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+	}{
+		{
+			name:    "Admin role",
+			desired: "email=foooo@bar.com&uid=10&role=admin",
+		},
+		{
+			name:    "Email spanning blocks",
+			desired: "email=a.very.long.address@bar.co.uk&uid=10&role=admin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, dec := profileOracles(RandomKey(16))
+			tmpl := Template{
+				Prefix:    []byte("email="),
+				Suffix:    []byte("&uid=10&role=user"),
+				BlockSize: 16,
+				Forbidden: []byte("&="),
+			}
+
+			forged, err := CutAndPaste(enc, dec, tmpl, []byte(tt.desired))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			pt, _ := dec(forged)
+			if !bytes.HasPrefix(pt, []byte(tt.desired)) {
+				t.Fatalf("Expected plain text %q, but got %q", tt.desired, pt)
+			}
+		})
+	}
+}
+
+func TestPlanCutAndPaste(t *testing.T) {
+	tmpl := Template{
+		Prefix:    []byte("email="),
+		Suffix:    []byte("&uid=10&role=user"),
+		BlockSize: 16,
+		Forbidden: []byte("&="),
+	}
+	// "&role=admin" can never be aligned, as the forbidden bytes cannot be supplied as input
+	if _, err := PlanCutAndPaste(tmpl, []byte("email=foo@bar.com&uid=10&role=admin")); err != NoCutFoundErr {
+		t.Fatalf("Expected error %s, but got %s", NoCutFoundErr, err)
+	}
+}
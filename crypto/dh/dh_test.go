@@ -0,0 +1,137 @@
+package dh
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestModExp(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		b := new(big.Int).Rand(r, Modp1536.P)
+		e := new(big.Int).Rand(r, Modp1536.P)
+		expected := new(big.Int).Exp(b, e, Modp1536.P)
+		if actual := ModExp(b, e, Modp1536.P); expected.Cmp(actual) != 0 {
+			t.Fatalf("Expected %s, but got %s", expected, actual)
+		}
+	}
+	if actual := ModExp(big.NewInt(5), big.NewInt(0), big.NewInt(37)); actual.Cmp(one) != 0 {
+		t.Fatalf("Expected %d, but got %s", 1, actual)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	for _, g := range []Group{Modp1536, Modp2048} {
+		t.Run(g.Name, func(t *testing.T) {
+			if !g.P.ProbablyPrime(20) {
+				t.Fatalf("Expected p to be prime")
+			}
+			q := new(big.Int).Rsh(g.P, 1)
+			if !q.ProbablyPrime(20) {
+				t.Fatalf("Expected (p-1)/2 to be prime")
+			}
+		})
+	}
+	if Modp2048.P.BitLen() != 2048 || Modp1536.P.BitLen() != 1536 {
+		t.Fatalf("Unexpected group sizes")
+	}
+}
+
+func TestNistGroups(t *testing.T) {
+	tests := []struct {
+		g     Group
+		pbits int
+		qbits int
+	}{
+		{Nist1024_160, 1024, 160},
+		{Nist2048_224, 2048, 224},
+		{Nist2048_256, 2048, 256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.g.Name, func(t *testing.T) {
+			if tt.g.P.BitLen() != tt.pbits || tt.g.Q.BitLen() != tt.qbits {
+				t.Fatalf("Expected %d and %d bit p and q, but got %d and %d", tt.pbits, tt.qbits, tt.g.P.BitLen(), tt.g.Q.BitLen())
+			}
+			if !tt.g.P.ProbablyPrime(20) || !tt.g.Q.ProbablyPrime(20) {
+				t.Fatalf("Expected p and q to be prime")
+			}
+			// g generates the subgroup of order q
+			if actual := ModExp(tt.g.G, tt.g.Q, tt.g.P); actual.Cmp(one) != 0 {
+				t.Fatalf("Expected g^q = 1, but got %s", actual)
+			}
+		})
+	}
+}
+
+func TestShared(t *testing.T) {
+	for _, g := range []Group{Toy, Modp1536, Nist1024_160} {
+		t.Run(g.Name, func(t *testing.T) {
+			a, err := GenerateKey(g)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			b, err := GenerateKey(g)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			sa, sb := a.Shared(b.Y), b.Shared(a.Y)
+			if sa.Cmp(sb) != 0 {
+				t.Fatalf("Expected shared secrets to be equal, but got %s and %s", sa, sb)
+			}
+			if !bytes.Equal(Sha1Key(sa), Sha1Key(sb)) || len(Sha256Key(sa)) != KeySize {
+				t.Fatalf("Unexpected derived keys")
+			}
+		})
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	msgs := [][]byte{
+		[]byte("YELLOW SUBMARINE"),
+		[]byte("some kind of somewhat long plain text!"),
+	}
+	tests := []struct {
+		name string
+		mitm *Mitm
+	}{
+		{
+			name: "No man-in-the-middle",
+		},
+		{
+			name: "Key fixing",
+			mitm: KeyFixing(),
+		},
+		{
+			name: "g = 1",
+			mitm: MaliciousGOne(),
+		},
+		{
+			name: "g = p",
+			mitm: MaliciousGP(),
+		},
+		{
+			name: "g = p - 1",
+			mitm: MaliciousGPMinusOne(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				if err := Run(Modp1536, msgs, tt.mitm); err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if tt.mitm == nil {
+					continue
+				}
+				for j, msg := range msgs {
+					if !bytes.Equal(msg, tt.mitm.Recovered[j]) {
+						t.Fatalf("Expected recovered message %q, but got %q", msg, tt.mitm.Recovered[j])
+					}
+				}
+				tt.mitm.Recovered = nil
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package dh
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	NoSecretErr = errors.New("none of the candidate shared secrets decrypts the message")
+)
+
+// Mitm sits between Alice and Bob, tampers with the negotiation and key exchange, and relays the encrypted messages
+// by decrypting them with the shared secret it forced upon each side and re-encrypting them for the other side
+type Mitm struct {
+	// Group tampers with the group proposed to Bob
+	Group func(p, g *big.Int) *big.Int
+	// ToBob and ToAlice tamper with the public keys sent to either party
+	ToBob   func(p, g, y *big.Int) *big.Int
+	ToAlice func(p, g, y *big.Int) *big.Int
+	// Secrets returns the candidates for the shared secrets Alice and Bob end up with
+	Secrets func(p, g *big.Int) (alice []*big.Int, bob []*big.Int)
+
+	// Recovered holds the plain text of all messages sent by Alice
+	Recovered [][]byte
+}
+
+func printable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// decrypt tries every candidate shared secret and returns the plain text and the key of the one that works.
+// A wrong key occasionally yields valid padding, so printable plain texts are preferred.
+func decrypt(m Encrypted, secrets []*big.Int) ([]byte, []byte, error) {
+	var pt, key []byte
+	for _, s := range secrets {
+		k := Sha1Key(s)
+		candidate, err := Decrypt(k, m)
+		if err != nil {
+			continue
+		}
+		if pt == nil || printable(candidate) && !printable(pt) {
+			pt, key = candidate, k
+		}
+	}
+	if pt == nil {
+		return nil, nil, NoSecretErr
+	}
+	return pt, key, nil
+}
+
+func (mitm *Mitm) Run(alice, bob *Conn) error {
+	defer bob.Close()
+	defer alice.Close()
+
+	m, err := alice.Receive()
+	if err != nil {
+		return err
+	}
+	n, ok := m.(Negotiate)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	p, g := n.P, n.G
+	gBob := g
+	if mitm.Group != nil {
+		gBob = mitm.Group(p, g)
+	}
+	bob.Send(Negotiate{p, gBob})
+
+	m, err = bob.Receive()
+	if err != nil {
+		return err
+	}
+	if _, ok := m.(Ack); !ok {
+		return UnexpectedMessageErr
+	}
+	alice.Send(Ack{p, g})
+
+	m, err = alice.Receive()
+	if err != nil {
+		return err
+	}
+	a, ok := m.(PublicKey)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	if mitm.ToBob != nil {
+		a.Y = mitm.ToBob(p, g, a.Y)
+	}
+	bob.Send(a)
+
+	m, err = bob.Receive()
+	if err != nil {
+		return err
+	}
+	b, ok := m.(PublicKey)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	if mitm.ToAlice != nil {
+		b.Y = mitm.ToAlice(p, g, b.Y)
+	}
+	alice.Send(b)
+
+	secretsAlice, secretsBob := mitm.Secrets(p, g)
+	for {
+		m, err := alice.Receive()
+		if err == ClosedErr {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e, ok := m.(Encrypted)
+		if !ok {
+			return UnexpectedMessageErr
+		}
+		pt, keyAlice, err := decrypt(e, secretsAlice)
+		if err != nil {
+			return err
+		}
+		mitm.Recovered = append(mitm.Recovered, pt)
+
+		e, err = Encrypt(Sha1Key(secretsBob[0]), pt)
+		if err != nil {
+			return err
+		}
+		bob.Send(e)
+
+		m, err = bob.Receive()
+		if err != nil {
+			return err
+		}
+		echo, ok := m.(Encrypted)
+		if !ok {
+			return UnexpectedMessageErr
+		}
+		pt, _, err = decrypt(echo, secretsBob)
+		if err != nil {
+			return err
+		}
+		e, err = Encrypt(keyAlice, pt)
+		if err != nil {
+			return err
+		}
+		alice.Send(e)
+	}
+}
+
+func constant(i *big.Int) []*big.Int {
+	return []*big.Int{i}
+}
+
+// KeyFixing replaces both public keys by p, so both shared secrets are 0 (challenge 34)
+func KeyFixing() *Mitm {
+	return &Mitm{
+		ToBob: func(p, g, y *big.Int) *big.Int {
+			return p
+		},
+		ToAlice: func(p, g, y *big.Int) *big.Int {
+			return p
+		},
+		Secrets: func(p, g *big.Int) ([]*big.Int, []*big.Int) {
+			return constant(zero), constant(zero)
+		},
+	}
+}
+
+// MaliciousGOne proposes g = 1 to Bob, so his public key and Alice's shared secret are 1 (challenge 35)
+func MaliciousGOne() *Mitm {
+	return &Mitm{
+		Group: func(p, g *big.Int) *big.Int {
+			return one
+		},
+		ToBob: func(p, g, y *big.Int) *big.Int {
+			return one
+		},
+		Secrets: func(p, g *big.Int) ([]*big.Int, []*big.Int) {
+			return constant(one), constant(one)
+		},
+	}
+}
+
+// MaliciousGP proposes g = p to Bob, so his public key and Alice's shared secret are 0 (challenge 35)
+func MaliciousGP() *Mitm {
+	return &Mitm{
+		Group: func(p, g *big.Int) *big.Int {
+			return p
+		},
+		ToBob: func(p, g, y *big.Int) *big.Int {
+			return zero
+		},
+		Secrets: func(p, g *big.Int) ([]*big.Int, []*big.Int) {
+			return constant(zero), constant(zero)
+		},
+	}
+}
+
+// MaliciousGPMinusOne proposes g = p-1 to Bob, so his public key is 1 or p-1, and so is Alice's shared secret (challenge 35)
+func MaliciousGPMinusOne() *Mitm {
+	return &Mitm{
+		Group: func(p, g *big.Int) *big.Int {
+			return new(big.Int).Sub(p, one)
+		},
+		ToBob: func(p, g, y *big.Int) *big.Int {
+			return one
+		},
+		Secrets: func(p, g *big.Int) ([]*big.Int, []*big.Int) {
+			return []*big.Int{one, new(big.Int).Sub(p, one)}, constant(one)
+		},
+	}
+}
@@ -0,0 +1,216 @@
+package dh
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"math/big"
+)
+
+var (
+	UnexpectedMessageErr = errors.New("received unexpected message")
+	ClosedErr            = errors.New("connection closed")
+	EchoMismatchErr      = errors.New("echoed message does not match the sent message")
+)
+
+// Messages of the protocol: Alice proposes a group, Bob acknowledges it, they exchange public keys,
+// and then Alice sends encrypted messages that Bob echoes back
+type (
+	Negotiate struct {
+		P *big.Int
+		G *big.Int
+	}
+	Ack struct {
+		P *big.Int
+		G *big.Int
+	}
+	PublicKey struct {
+		Y *big.Int
+	}
+	Encrypted struct {
+		Ct []byte
+		Iv []byte
+	}
+)
+
+type Message interface{}
+
+// Conn is one end of a bidirectional message channel
+type Conn struct {
+	in  <-chan Message
+	out chan<- Message
+}
+
+// Pipe returns the two ends of a message channel
+func Pipe() (*Conn, *Conn) {
+	ab := make(chan Message, 1)
+	ba := make(chan Message, 1)
+	return &Conn{in: ba, out: ab}, &Conn{in: ab, out: ba}
+}
+
+func (c *Conn) Send(m Message) {
+	c.out <- m
+}
+
+func (c *Conn) Receive() (Message, error) {
+	m, ok := <-c.in
+	if !ok {
+		return nil, ClosedErr
+	}
+	return m, nil
+}
+
+func (c *Conn) Close() {
+	close(c.out)
+}
+
+func Encrypt(key []byte, pt []byte) (Encrypted, error) {
+	iv := crypto.RandomKey(aes.BlockSize)
+	ct, err := crypto.EncryptCbc(pt, key, iv)
+	return Encrypted{ct, iv}, err
+}
+
+func Decrypt(key []byte, m Encrypted) ([]byte, error) {
+	return crypto.DecryptCbc(m.Ct, key, m.Iv)
+}
+
+// Alice negotiates the group, exchanges keys and sends each message, checking that Bob echoes it back
+func Alice(conn *Conn, g Group, msgs [][]byte) error {
+	defer conn.Close()
+
+	conn.Send(Negotiate{g.P, g.G})
+	m, err := conn.Receive()
+	if err != nil {
+		return err
+	}
+	if _, ok := m.(Ack); !ok {
+		return UnexpectedMessageErr
+	}
+
+	priv, err := GenerateKey(g)
+	if err != nil {
+		return err
+	}
+	conn.Send(PublicKey{priv.Y})
+	m, err = conn.Receive()
+	if err != nil {
+		return err
+	}
+	peer, ok := m.(PublicKey)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	key := Sha1Key(priv.Shared(peer.Y))
+
+	for _, msg := range msgs {
+		e, err := Encrypt(key, msg)
+		if err != nil {
+			return err
+		}
+		conn.Send(e)
+
+		m, err := conn.Receive()
+		if err != nil {
+			return err
+		}
+		echo, ok := m.(Encrypted)
+		if !ok {
+			return UnexpectedMessageErr
+		}
+		pt, err := Decrypt(key, echo)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pt, msg) {
+			return EchoMismatchErr
+		}
+	}
+	return nil
+}
+
+// Bob accepts the group Alice proposes, exchanges keys and echoes every message until the connection is closed
+func Bob(conn *Conn) error {
+	defer conn.Close()
+
+	m, err := conn.Receive()
+	if err != nil {
+		return err
+	}
+	n, ok := m.(Negotiate)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	g := Group{P: n.P, G: n.G}
+	conn.Send(Ack{n.P, n.G})
+
+	m, err = conn.Receive()
+	if err != nil {
+		return err
+	}
+	peer, ok := m.(PublicKey)
+	if !ok {
+		return UnexpectedMessageErr
+	}
+	priv, err := GenerateKey(g)
+	if err != nil {
+		return err
+	}
+	conn.Send(PublicKey{priv.Y})
+	key := Sha1Key(priv.Shared(peer.Y))
+
+	for {
+		m, err := conn.Receive()
+		if err == ClosedErr {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e, ok := m.(Encrypted)
+		if !ok {
+			return UnexpectedMessageErr
+		}
+		pt, err := Decrypt(key, e)
+		if err != nil {
+			return err
+		}
+		echo, err := Encrypt(key, pt)
+		if err != nil {
+			return err
+		}
+		conn.Send(echo)
+	}
+}
+
+// Run lets Alice send the messages to Bob over a channel, optionally through a man-in-the-middle
+func Run(g Group, msgs [][]byte, mitm *Mitm) error {
+	alice, bobSide := Pipe()
+	errs := make(chan error, 2)
+
+	if mitm != nil {
+		mitmAlice := bobSide
+		mitmBob, bob := Pipe()
+		go func() {
+			errs <- Bob(bob)
+		}()
+		go func() {
+			errs <- mitm.Run(mitmAlice, mitmBob)
+		}()
+	} else {
+		go func() {
+			errs <- Bob(bobSide)
+		}()
+		errs <- nil
+	}
+
+	if err := Alice(alice, g, msgs); err != nil {
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
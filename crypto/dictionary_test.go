@@ -12,7 +12,7 @@ func TestEnDict(t *testing.T) {
 		s += v
 	}
 	expected := 1.0 - crypto.NonAlphabetic
-	if s != expected {
+	if math.Abs(s-expected) > 1e-9 {
 		t.Fatalf("Dictionary weights MUST sum to %f, but are %f", expected, s)
 	}
 }
@@ -20,12 +20,17 @@ func TestEnDict(t *testing.T) {
 func TestScore(t *testing.T) {
 	input := "abc"
 
-	expected := math.Pow(3*crypto.EnDict['a']-1, 2) +
-		math.Pow(3*crypto.EnDict['b']-1, 2) +
-		math.Pow(3*crypto.EnDict['c']-1, 2)
+	expected := math.Pow(3*crypto.NonAlphabetic, 2)
+	for k, v := range crypto.EnDict {
+		actual := 0.0
+		if k == 'a' || k == 'b' || k == 'c' {
+			actual = 1
+		}
+		expected += math.Pow(3*v-actual, 2)
+	}
 
-	actual := crypto.ChiSquared(input)
-	if actual != expected {
+	actual := crypto.ChiSquared(input, false)
+	if math.Abs(actual-expected) > 1e-9 {
 		t.Fatalf("Expected %f, but got %f", expected, actual)
 	}
 }
@@ -0,0 +1,120 @@
+package dsa
+
+import (
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"math/big"
+)
+
+var (
+	NonceNotFoundErr   = errors.New("no nonce in range reproduces the signature")
+	NoRepeatedNonceErr = errors.New("no two signatures share a nonce")
+	KeyMismatchErr     = errors.New("recovered key does not match the public key")
+)
+
+// SignedHash is a signature together with the hash it signs
+type SignedHash struct {
+	H   *big.Int
+	Sig Signature
+}
+
+// KeyFromNonce computes the private key from a signature and the nonce that was used to create it:
+// x = (s * k - H(m)) / r mod q
+func KeyFromNonce(p Params, sh SignedHash, nonce *big.Int) (*big.Int, error) {
+	rInv, err := rsa.InvMod(sh.Sig.R, p.Q)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).Mul(sh.Sig.S, nonce)
+	x.Sub(x, sh.H)
+	x.Mul(x, rInv)
+	return x.Mod(x, p.Q), nil
+}
+
+// RecoverFromNonceRange recovers the private key from a signature whose nonce lies in [lower, upper].
+// It returns the private key and the number of nonces that were tried.
+func RecoverFromNonceRange(pub *PublicKey, sh SignedHash, lower, upper uint64) (*PrivateKey, uint64, error) {
+	r := new(big.Int)
+	tried := uint64(0)
+	for k := lower; k <= upper && k >= lower; k++ {
+		tried++
+		nonce := new(big.Int).SetUint64(k)
+		// cheaply filter on r before computing the key
+		r.Exp(pub.G, nonce, pub.P)
+		r.Mod(r, pub.Q)
+		if r.Cmp(sh.Sig.R) != 0 {
+			continue
+		}
+		x, err := KeyFromNonce(pub.Params, sh, nonce)
+		if err != nil {
+			return nil, tried, err
+		}
+		if k := NewPrivateKey(pub.Params, x); k.Y.Cmp(pub.Y) == 0 {
+			return k, tried, nil
+		}
+	}
+	return nil, tried, NonceNotFoundErr
+}
+
+// RecoverFromRepeatedNonce recovers the private key from two signatures that were created with the same nonce,
+// which are recognized by having the same r. The nonce is k = (H(m1) - H(m2)) / (s1 - s2) mod q.
+func RecoverFromRepeatedNonce(pub *PublicKey, signed []SignedHash) (*PrivateKey, error) {
+	q := pub.Q
+	mismatch := false
+	for i := range signed {
+		for j := i + 1; j < len(signed); j++ {
+			a, b := signed[i], signed[j]
+			if a.Sig.R.Cmp(b.Sig.R) != 0 || a.Sig.S.Cmp(b.Sig.S) == 0 {
+				continue
+			}
+			ds := new(big.Int).Sub(a.Sig.S, b.Sig.S)
+			ds.Mod(ds, q)
+			dsInv, err := rsa.InvMod(ds, q)
+			if err != nil {
+				continue
+			}
+			nonce := new(big.Int).Sub(a.H, b.H)
+			nonce.Mul(nonce, dsInv)
+			nonce.Mod(nonce, q)
+
+			x, err := KeyFromNonce(pub.Params, a, nonce)
+			if err != nil {
+				return nil, err
+			}
+			k := NewPrivateKey(pub.Params, x)
+			if k.Y.Cmp(pub.Y) != 0 {
+				// r can collide without the nonce being the same, so keep looking
+				mismatch = true
+				continue
+			}
+			return k, nil
+		}
+	}
+	if mismatch {
+		return nil, KeyMismatchErr
+	}
+	return nil, NoRepeatedNonceErr
+}
+
+// ZeroGeneratorSignature returns a signature that verifies for any message when g = 0 and the verifier does not
+// check that r is non-zero, since then v = 0 regardless of the message
+func ZeroGeneratorSignature() Signature {
+	return Signature{
+		R: big.NewInt(0),
+		S: big.NewInt(1),
+	}
+}
+
+// MagicSignature forges a signature that verifies for any message when g = p + 1 (or any g = 1 mod p).
+// With z arbitrary, r = (y^z mod p) mod q and s = r / z mod q verify since g^u1 = 1.
+func MagicSignature(pub *PublicKey, z *big.Int) (Signature, error) {
+	zInv, err := rsa.InvMod(z, pub.Q)
+	if err != nil {
+		return Signature{}, err
+	}
+	r := new(big.Int).Exp(pub.Y, z, pub.P)
+	r.Mod(r, pub.Q)
+	s := new(big.Int).Mul(r, zInv)
+	s.Mod(s, pub.Q)
+	return Signature{r, s}, nil
+}
@@ -0,0 +1,201 @@
+// Package dsa implements the Digital Signature Algorithm, with verification checks that can be turned off.
+package dsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto/rsa"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"math/big"
+)
+
+var (
+	InvalidParamsErr    = errors.New("invalid DSA parameters")
+	InvalidSignatureErr = errors.New("invalid signature")
+	ZeroSignatureErr    = errors.New("signature has a zero component")
+)
+
+var (
+	one = big.NewInt(1)
+)
+
+func mustHex(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("dsa: invalid hex constant")
+	}
+	return i
+}
+
+type Params struct {
+	P *big.Int
+	Q *big.Int
+	G *big.Int
+}
+
+var (
+	// DefaultParams are the 1024-bit parameters used by cryptopals
+	DefaultParams = Params{
+		P: mustHex("800000000000000089e1855218a0e7dac38136ffafa72eda7859f2171e25e65eac698c1702578b07dc2a1076da" +
+			"241c76c62d374d8389ea5aeffd3226a0530cc565f3bf6b50929139ebeac04f48c3c84afb796d61e5a4f9a8fda812ab594942" +
+			"32c7d2b4deb50aa18ee9e132bfa85ac4374d7f9091abc3d015efc871a584471bb1"),
+		Q: mustHex("f4f47f05794b256174bba6e9b396a7707e563c5b"),
+		G: mustHex("5958c9d3898b224b12672c0b98e06c60df923cb8bc999d119458fef538b8fa4046c8db53039db620c094c9fa077e" +
+			"f389b5322a559946a71903f990f1f7e0e025e2d7f7cf494aff1a0470f5b64c36b625a097f1651fe775323556fe00b3608c88" +
+			"7892878480e99041be601a62166ca6894bdd41a7054ec89f756ba9fc95302291"),
+	}
+)
+
+// WithG returns a copy of the parameters with a different generator
+func (p Params) WithG(g *big.Int) Params {
+	return Params{
+		P: p.P,
+		Q: p.Q,
+		G: new(big.Int).Set(g),
+	}
+}
+
+type PublicKey struct {
+	Params
+	Y *big.Int
+}
+
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+type Signature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// Hash returns the SHA-1 digest of the message as an integer
+func Hash(msg []byte) *big.Int {
+	h := sha1.New()
+	h.Write(msg)
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// GenerateKey returns a random private key in [1, q-1] and the public key g^x mod p
+func GenerateKey(p Params) (*PrivateKey, error) {
+	x, err := rand.Int(rand.Reader, new(big.Int).Sub(p.Q, one))
+	if err != nil {
+		return nil, err
+	}
+	x.Add(x, one)
+	return NewPrivateKey(p, x), nil
+}
+
+// NewPrivateKey derives the public key for the private key x
+func NewPrivateKey(p Params, x *big.Int) *PrivateKey {
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			Params: p,
+			Y:      new(big.Int).Exp(p.G, x, p.P),
+		},
+		X: new(big.Int).Set(x),
+	}
+}
+
+// Checks configures which checks are performed when signing and verifying.
+// Leaving them out reproduces implementations that can be tricked with malicious parameters.
+type Checks struct {
+	// Range rejects signatures with r or s outside of [1, q-1], and makes signing retry zero components
+	Range bool
+	// Generator rejects parameters with g outside of [2, p-1]
+	Generator bool
+}
+
+var (
+	Secure   = Checks{Range: true, Generator: true}
+	Insecure = Checks{}
+)
+
+func (c Checks) checkParams(p Params) error {
+	if c.Generator && (p.G.Cmp(one) <= 0 || p.G.Cmp(p.P) >= 0) {
+		return InvalidParamsErr
+	}
+	return nil
+}
+
+// SignNonce signs the hash with the given nonce
+func (c Checks) SignNonce(k *PrivateKey, h, nonce *big.Int) (Signature, error) {
+	p := k.Params
+	if err := c.checkParams(p); err != nil {
+		return Signature{}, err
+	}
+	kInv, err := rsa.InvMod(nonce, p.Q)
+	if err != nil {
+		return Signature{}, err
+	}
+	// r = (g^k mod p) mod q
+	r := new(big.Int).Exp(p.G, nonce, p.P)
+	r.Mod(r, p.Q)
+
+	// s = k^-1 (H(m) + xr) mod q
+	s := new(big.Int).Mul(k.X, r)
+	s.Add(s, h)
+	s.Mul(s, kInv)
+	s.Mod(s, p.Q)
+
+	if c.Range && (r.Sign() == 0 || s.Sign() == 0) {
+		return Signature{}, ZeroSignatureErr
+	}
+	return Signature{r, s}, nil
+}
+
+// Sign signs the hash with a random nonce
+func (c Checks) Sign(k *PrivateKey, h *big.Int) (Signature, error) {
+	for {
+		nonce, err := rand.Int(rand.Reader, new(big.Int).Sub(k.Q, one))
+		if err != nil {
+			return Signature{}, err
+		}
+		nonce.Add(nonce, one)
+		sig, err := c.SignNonce(k, h, nonce)
+		if err == ZeroSignatureErr {
+			continue
+		}
+		return sig, err
+	}
+}
+
+// Verify checks the signature of the hash under the public key
+func (c Checks) Verify(pub *PublicKey, h *big.Int, sig Signature) error {
+	p := pub.Params
+	if err := c.checkParams(p); err != nil {
+		return err
+	}
+	if c.Range && (sig.R.Sign() <= 0 || sig.R.Cmp(p.Q) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(p.Q) >= 0) {
+		return InvalidSignatureErr
+	}
+	w, err := rsa.InvMod(sig.S, p.Q)
+	if err != nil {
+		return InvalidSignatureErr
+	}
+	u1 := new(big.Int).Mul(h, w)
+	u1.Mod(u1, p.Q)
+	u2 := new(big.Int).Mul(sig.R, w)
+	u2.Mod(u2, p.Q)
+
+	// v = (g^u1 * y^u2 mod p) mod q
+	v := new(big.Int).Exp(p.G, u1, p.P)
+	v.Mul(v, new(big.Int).Exp(pub.Y, u2, p.P))
+	v.Mod(v, p.P)
+	v.Mod(v, p.Q)
+	if v.Cmp(sig.R) != 0 {
+		return InvalidSignatureErr
+	}
+	return nil
+}
+
+// Sign signs the hash with all checks enabled
+func Sign(k *PrivateKey, h *big.Int) (Signature, error) {
+	return Secure.Sign(k, h)
+}
+
+// Verify verifies the signature of the hash with all checks enabled
+func Verify(pub *PublicKey, h *big.Int, sig Signature) error {
+	return Secure.Verify(pub, h, sig)
+}
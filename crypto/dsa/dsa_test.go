@@ -0,0 +1,167 @@
+package dsa
+
+import (
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"math/big"
+	"testing"
+)
+
+func TestDefaultParams(t *testing.T) {
+	p := DefaultParams
+	if !p.P.ProbablyPrime(20) || !p.Q.ProbablyPrime(20) {
+		t.Fatalf("Expected p and q to be prime")
+	}
+	if new(big.Int).Mod(new(big.Int).Sub(p.P, one), p.Q).Sign() != 0 {
+		t.Fatalf("Expected q to divide p - 1")
+	}
+	if new(big.Int).Exp(p.G, p.Q, p.P).Cmp(one) != 0 {
+		t.Fatalf("Expected g to have order q")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	k, err := GenerateKey(DefaultParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	h := Hash([]byte("sign me"))
+	sig, err := Sign(k, h)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := Verify(&k.PublicKey, h, sig); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := Verify(&k.PublicKey, Hash([]byte("not me")), sig); err != InvalidSignatureErr {
+		t.Fatalf("Expected error %s, but got %v", InvalidSignatureErr, err)
+	}
+	tampered := Signature{sig.R, new(big.Int).Add(sig.S, one)}
+	if err := Verify(&k.PublicKey, h, tampered); err != InvalidSignatureErr {
+		t.Fatalf("Expected error %s, but got %v", InvalidSignatureErr, err)
+	}
+}
+
+func fingerprint(x *big.Int) string {
+	h := sha1.New()
+	h.Write([]byte(x.Text(16)))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func TestRecoverFromNonceRange(t *testing.T) {
+	// the signature from cryptopals challenge 43
+	y := mustHex("84ad4719d044495496a3201c8ff484feb45b962e7302e56a392aee4abab3e4bdebf2955b4736012f21a08084056b19bcd7f" +
+		"ee56048e004e44984e2f411788efdc837a0d2e5abb7b555039fd243ac01f0fb2ed1dec568280ce678e931868d23eb095fde9d37" +
+		"79191b8c0299d6e07bbb283e6633451e535c45513b2d33c99ea17")
+	msg := []byte("For those that envy a MC it can be hazardous to your health\n" +
+		"So be friendly, a matter of life and death, just like a etch-a-sketch\n")
+	r, _ := new(big.Int).SetString("548099063082341131477253921760299949438196259240", 10)
+	s, _ := new(big.Int).SetString("857042759984254168557880549501802188789837994940", 10)
+
+	h := Hash(msg)
+	if expected := mustHex("d2d0714f014a9784047eaeccf956520045c45265"); h.Cmp(expected) != 0 {
+		t.Fatalf("Expected hash %x, but got %x", expected, h)
+	}
+	pub := &PublicKey{DefaultParams, y}
+	sh := SignedHash{h, Signature{r, s}}
+	if err := Verify(pub, h, sh.Sig); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	k, tried, err := RecoverFromNonceRange(pub, sh, 0, 1<<16)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := "0954edd5e0afe5542a4adf012611a91912a3ec16"; fingerprint(k.X) != expected {
+		t.Fatalf("Expected key fingerprint %s, but got %s", expected, fingerprint(k.X))
+	}
+	if tried == 0 || tried > 1<<16+1 {
+		t.Fatalf("Unexpected number of tried nonces: %d", tried)
+	}
+
+	if _, _, err := RecoverFromNonceRange(pub, sh, 0, 10); err != NonceNotFoundErr {
+		t.Fatalf("Expected error %s, but got %v", NonceNotFoundErr, err)
+	}
+}
+
+func TestRecoverFromRepeatedNonce(t *testing.T) {
+	k, err := GenerateKey(DefaultParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var signed []SignedHash
+	for i, nonce := range []int64{1234, 5678, 91011, 5678} {
+		h := Hash([]byte(fmt.Sprintf("message %d", i)))
+		sig, err := Secure.SignNonce(k, h, big.NewInt(nonce))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		signed = append(signed, SignedHash{h, sig})
+	}
+
+	if _, err := RecoverFromRepeatedNonce(&k.PublicKey, signed[:3]); err != NoRepeatedNonceErr {
+		t.Fatalf("Expected error %s, but got %v", NoRepeatedNonceErr, err)
+	}
+
+	// a bogus signature that shares r with the first one yields the wrong key, which must not stop the search
+	bogus := SignedHash{Hash([]byte("bogus")), Signature{signed[0].Sig.R, big.NewInt(42)}}
+	if _, err := RecoverFromRepeatedNonce(&k.PublicKey, []SignedHash{signed[0], bogus}); err != KeyMismatchErr {
+		t.Fatalf("Expected error %s, but got %v", KeyMismatchErr, err)
+	}
+	signed = append([]SignedHash{bogus}, signed...)
+
+	actual, err := RecoverFromRepeatedNonce(&k.PublicKey, signed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual.X.Cmp(k.X) != 0 {
+		t.Fatalf("Expected key %s, but got %s", k.X, actual.X)
+	}
+}
+
+func TestMaliciousGenerator(t *testing.T) {
+	msgs := [][]byte{[]byte("Hello, world"), []byte("Goodbye, world")}
+
+	t.Run("g = 0", func(t *testing.T) {
+		k, err := GenerateKey(DefaultParams.WithG(big.NewInt(0)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := Sign(k, Hash(msgs[0])); err != InvalidParamsErr {
+			t.Fatalf("Expected error %s, but got %v", InvalidParamsErr, err)
+		}
+		sig := ZeroGeneratorSignature()
+		for _, msg := range msgs {
+			if err := Insecure.Verify(&k.PublicKey, Hash(msg), sig); err != nil {
+				t.Fatalf("Expected insecure verification to pass, but got %s", err)
+			}
+			if err := (Checks{Generator: true}).Verify(&k.PublicKey, Hash(msg), sig); err != InvalidParamsErr {
+				t.Fatalf("Expected error %s, but got %v", InvalidParamsErr, err)
+			}
+			if err := (Checks{Range: true}).Verify(&k.PublicKey, Hash(msg), sig); err != InvalidSignatureErr {
+				t.Fatalf("Expected error %s, but got %v", InvalidSignatureErr, err)
+			}
+		}
+	})
+
+	t.Run("g = p + 1", func(t *testing.T) {
+		k, err := GenerateKey(DefaultParams)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pub := &PublicKey{DefaultParams.WithG(new(big.Int).Add(DefaultParams.P, one)), k.Y}
+		sig, err := MagicSignature(pub, big.NewInt(42))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for _, msg := range msgs {
+			// the range check does not help against this forgery
+			if err := (Checks{Range: true}).Verify(pub, Hash(msg), sig); err != nil {
+				t.Fatalf("Expected verification without generator check to pass, but got %s", err)
+			}
+			if err := Secure.Verify(pub, Hash(msg), sig); err != InvalidParamsErr {
+				t.Fatalf("Expected error %s, but got %v", InvalidParamsErr, err)
+			}
+		}
+	})
+}
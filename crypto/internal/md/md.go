@@ -0,0 +1,110 @@
+// Package md holds the Merkle–Damgård plumbing shared by the MD4 and SHA-1 implementations:
+// buffering input into blocks, length padding and converting between digests and chaining values.
+package md
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	BlockSize = 64
+)
+
+var (
+	InvalidLengthErr = errors.New("processed length must be a multiple of the block size")
+)
+
+// Compress updates the chaining value h with a single block
+type Compress func(h []uint32, p []byte)
+
+// Digest feeds its input block by block into a compression function. The byte order determines how
+// the message length in the padding and the words of the final digest are encoded.
+type Digest struct {
+	h        []uint32
+	x        [BlockSize]byte
+	nx       int
+	len      uint64
+	init     []uint32
+	length   uint64
+	order    binary.ByteOrder
+	compress Compress
+}
+
+// New returns a digest whose chaining value starts at h, with length bytes already accounted for
+func New(h []uint32, length uint64, order binary.ByteOrder, compress Compress) *Digest {
+	d := &Digest{
+		init:     append([]uint32(nil), h...),
+		length:   length,
+		order:    order,
+		compress: compress,
+	}
+	d.Reset()
+	return d
+}
+
+// Pad returns the padding for a message of the given length in bytes: a one bit, zeros up to
+// 8 bytes short of a block boundary, and the bit length encoded in the given byte order
+func Pad(length uint64, order binary.ByteOrder) []byte {
+	padlen := BlockSize - int((length+8)%BlockSize)
+	pad := make([]byte, padlen+8)
+	pad[0] = 0x80
+	order.PutUint64(pad[padlen:], length*8)
+	return pad
+}
+
+// Decode fills h with the words of sum in the given byte order, so len(sum) must be 4 * len(h)
+func Decode(h []uint32, sum []byte, order binary.ByteOrder) {
+	for i := range h {
+		h[i] = order.Uint32(sum[4*i:])
+	}
+}
+
+func (d *Digest) Reset() {
+	d.h = append(d.h[:0], d.init...)
+	d.nx = 0
+	d.len = d.length
+}
+
+func (d *Digest) Size() int {
+	return 4 * len(d.init)
+}
+
+func (d *Digest) BlockSize() int {
+	return BlockSize
+}
+
+func (d *Digest) Write(p []byte) (int, error) {
+	nn := len(p)
+	d.len += uint64(nn)
+	if d.nx > 0 {
+		n := copy(d.x[d.nx:], p)
+		d.nx += n
+		if d.nx == BlockSize {
+			d.compress(d.h, d.x[:])
+			d.nx = 0
+		}
+		p = p[n:]
+	}
+	for len(p) >= BlockSize {
+		d.compress(d.h, p[:BlockSize])
+		p = p[BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return nn, nil
+}
+
+func (d *Digest) Sum(in []byte) []byte {
+	// pad a copy with its own chaining value, so the caller can keep writing to d
+	c := *d
+	c.h = append([]uint32(nil), d.h...)
+	c.Write(Pad(d.len, d.order))
+
+	res := make([]byte, 4*len(c.h))
+	for i, v := range c.h {
+		d.order.PutUint32(res[4*i:], v)
+	}
+	return append(in, res...)
+}
@@ -0,0 +1,124 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"fmt"
+	"github.com/pkg/errors"
+	"runtime"
+	"sync"
+)
+
+var (
+	NoHighAsciiErr = errors.New("decryption oracle did not complain about the crafted cipher text")
+	KeyAsIvErr     = errors.New("key must not be used as IV")
+
+	ivWarnings   = map[IvWarning]int{}
+	ivWarningsMu sync.Mutex
+)
+
+// HighAsciiErr is returned by the decryption oracle when the plain text contains non-ASCII bytes, leaking the plain text
+type HighAsciiErr struct {
+	Pt []byte
+}
+
+func (err HighAsciiErr) Error() string {
+	return fmt.Sprintf("plain text contains high-ASCII bytes: %q", err.Pt)
+}
+
+// IvWarning is a call site that used the key as IV for CBC
+type IvWarning struct {
+	File string
+	Line int
+}
+
+// checkKeyAsIv records the caller of the CBC function if the key is reused as IV
+func checkKeyAsIv(key, iv []byte) {
+	if !bytes.Equal(key, iv) {
+		return
+	}
+	// skip checkKeyAsIv and the CBC function itself
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return
+	}
+	ivWarningsMu.Lock()
+	ivWarnings[IvWarning{file, line}]++
+	ivWarningsMu.Unlock()
+}
+
+// KeyAsIvWarnings returns the call sites that encrypted or decrypted with the key as IV, with the number of calls
+func KeyAsIvWarnings() map[IvWarning]int {
+	ivWarningsMu.Lock()
+	defer ivWarningsMu.Unlock()
+	res := map[IvWarning]int{}
+	for k, v := range ivWarnings {
+		res[k] = v
+	}
+	return res
+}
+
+// CbcOracles returns CBC encryption and decryption oracles for the given key and IV.
+// Unlike EncryptCbc and DecryptCbc, it refuses to use the key as IV.
+func CbcOracles(key, iv []byte) (Oracle, Oracle, error) {
+	if bytes.Equal(key, iv) {
+		return nil, nil, KeyAsIvErr
+	}
+	enc := func(pt []byte) ([]byte, error) {
+		return EncryptCbc(pt, key, iv)
+	}
+	dec := func(ct []byte) ([]byte, error) {
+		return DecryptCbc(ct, key, iv)
+	}
+	return enc, dec, nil
+}
+
+// KeyAsIvOracles returns CBC oracles that use the key as IV. The decryption oracle returns a HighAsciiErr
+// if the plain text contains bytes that are not ASCII.
+func KeyAsIvOracles(key []byte) (Oracle, Oracle) {
+	enc := func(pt []byte) ([]byte, error) {
+		return EncryptCbc(pt, key, key)
+	}
+	dec := func(ct []byte) ([]byte, error) {
+		pt, err := DecryptCbc(ct, key, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range pt {
+			if b >= 0x80 {
+				return nil, HighAsciiErr{pt}
+			}
+		}
+		return pt, nil
+	}
+	return enc, dec
+}
+
+// RecoverKeyAsIv recovers the key from CBC oracles that use the key as IV.
+// Decrypting C1 || 0 || C1 yields P1' = D(C1) ^ K and P3' = D(C1), so P1' ^ P3' = K.
+func RecoverKeyAsIv(encrypt, decrypt Oracle) ([]byte, error) {
+	bsize := aes.BlockSize
+	ct, err := encrypt(bytes.Repeat([]byte("A"), 3*bsize))
+	if err != nil {
+		return nil, err
+	}
+	blocks := InBlocks(ct, bsize)
+
+	var crafted []byte
+	crafted = append(crafted, blocks[0]...)
+	crafted = append(crafted, make([]byte, bsize)...)
+	crafted = append(crafted, blocks[0]...)
+	// keep the last blocks, so the padding remains valid
+	crafted = append(crafted, blocks[len(blocks)-2]...)
+	crafted = append(crafted, blocks[len(blocks)-1]...)
+
+	_, err = decrypt(crafted)
+	highAscii, ok := err.(HighAsciiErr)
+	if !ok {
+		if err != nil {
+			return nil, err
+		}
+		return nil, NoHighAsciiErr
+	}
+	return Xor(highAscii.Pt[:bsize], highAscii.Pt[2*bsize:3*bsize]), nil
+}
@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecoverKeyAsIv(t *testing.T) {
+	key := RandomKey(16)
+	enc, dec := KeyAsIvOracles(key)
+
+	actual, err := RecoverKeyAsIv(enc, dec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(key, actual) {
+		t.Fatalf("Expected key %x, but got %x", key, actual)
+	}
+}
+
+func TestCbcOracles(t *testing.T) {
+	key := RandomKey(16)
+	if _, _, err := CbcOracles(key, key); err != KeyAsIvErr {
+		t.Fatalf("Expected error %s, but got %v", KeyAsIvErr, err)
+	}
+
+	enc, dec, err := CbcOracles(key, RandomKey(16))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	pt := []byte("some plain text")
+	ct, err := enc(pt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	actual, err := dec(ct)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(pt, actual) {
+		t.Fatalf("Expected plain text %q, but got %q", pt, actual)
+	}
+}
+
+func TestKeyAsIvWarnings(t *testing.T) {
+	key := RandomKey(16)
+	EncryptCbc([]byte("some plain text"), key, RandomKey(16))
+	for w := range KeyAsIvWarnings() {
+		if filepath.Base(w.File) == "keyasiv_test.go" {
+			t.Fatalf("Unexpected warning for distinct key and IV at %s:%d", w.File, w.Line)
+		}
+	}
+
+	EncryptCbc([]byte("some plain text"), key, key)
+	found := false
+	for w, count := range KeyAsIvWarnings() {
+		if filepath.Base(w.File) == "keyasiv_test.go" && count == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected a warning for the call site in this test, but got %v", KeyAsIvWarnings())
+	}
+}
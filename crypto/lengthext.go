@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"github.com/pkg/errors"
+	"hash"
+)
+
+var (
+	NoForgeryFoundErr = errors.New("none of the forgeries was accepted")
+)
+
+// ExtendableHash is a Merkle-Damgard hash function whose state can be restored from a digest
+type ExtendableHash interface {
+	// Pad returns the padding that is appended to a message of the given length in bytes
+	Pad(length uint64) []byte
+	// FromDigest returns a hash that continues from the digest, after length bytes (including padding) were processed
+	FromDigest(sum []byte, length uint64) (hash.Hash, error)
+}
+
+type Forgery struct {
+	KeyLen  int
+	Message []byte
+	Mac     []byte
+}
+
+// LengthExtension forges the secret-prefix MAC of msg || glue || extension from the MAC of msg,
+// for every key length in [minKeyLen, maxKeyLen]
+func LengthExtension(h ExtendableHash, msg, mac, extension []byte, minKeyLen, maxKeyLen int) ([]Forgery, error) {
+	var res []Forgery
+	for keyLen := minKeyLen; keyLen <= maxKeyLen; keyLen++ {
+		length := uint64(keyLen + len(msg))
+		glue := h.Pad(length)
+
+		ext, err := h.FromDigest(mac, length+uint64(len(glue)))
+		if err != nil {
+			return nil, err
+		}
+		ext.Write(extension)
+
+		var forged []byte
+		forged = append(forged, msg...)
+		forged = append(forged, glue...)
+		forged = append(forged, extension...)
+
+		res = append(res, Forgery{
+			KeyLen:  keyLen,
+			Message: forged,
+			Mac:     ext.Sum(nil),
+		})
+	}
+	return res, nil
+}
+
+// LengthExtensionAttack returns the first forgery for a key length in [minKeyLen, maxKeyLen] that is accepted by the oracle
+func LengthExtensionAttack(h ExtendableHash, verify VerifyOracle, msg, mac, extension []byte, minKeyLen, maxKeyLen int) (Forgery, error) {
+	forgeries, err := LengthExtension(h, msg, mac, extension, minKeyLen, maxKeyLen)
+	if err != nil {
+		return Forgery{}, err
+	}
+	for _, f := range forgeries {
+		ok, err := verify(f.Message, f.Mac)
+		if err != nil {
+			return Forgery{}, err
+		}
+		if ok {
+			return f, nil
+		}
+	}
+	return Forgery{}, NoForgeryFoundErr
+}
@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"bytes"
+	"github.com/kdhageman/go-cryptopals/crypto/md4"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"testing"
+)
+
+func TestLengthExtension(t *testing.T) {
+	key := RandomKey(13)
+	msg := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	extension := []byte(";admin=true")
+
+	mac := sha1.Sum(append(append([]byte{}, key...), msg...))
+
+	forgeries, err := LengthExtension(sha1.Extender{}, msg, mac[:], extension, 0, 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(forgeries) != 33 {
+		t.Fatalf("Expected %d forgeries, but got %d", 33, len(forgeries))
+	}
+
+	valid := 0
+	for _, f := range forgeries {
+		expected := sha1.Sum(append(append([]byte{}, key...), f.Message...))
+		if !bytes.HasSuffix(f.Message, extension) {
+			t.Fatalf("Expected forged message to end with %q", extension)
+		}
+		if bytes.Equal(expected[:], f.Mac) {
+			if f.KeyLen != len(key) {
+				t.Fatalf("Expected forgery to be valid for key length %d, but got %d", len(key), f.KeyLen)
+			}
+			valid++
+		}
+	}
+	if valid != 1 {
+		t.Fatalf("Expected a single valid forgery, but got %d", valid)
+	}
+}
+
+func TestLengthExtensionMd4(t *testing.T) {
+	key := RandomKey(7)
+	msg := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	extension := []byte(";admin=true")
+
+	mac := md4.Sum(append(append([]byte{}, key...), msg...))
+
+	forgeries, err := LengthExtension(md4.Extender{}, msg, mac[:], extension, 7, 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := md4.Sum(append(append([]byte{}, key...), forgeries[0].Message...))
+	if !bytes.Equal(expected[:], forgeries[0].Mac) {
+		t.Fatalf("Expected forged MAC %x, but got %x", expected, forgeries[0].Mac)
+	}
+}
+
+func TestLengthExtensionAttack(t *testing.T) {
+	key := RandomKey(21)
+	mac, verify := SecretPrefix(sha1.New).Oracles(key, ConstantTimeCompare)
+	msg := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	extension := []byte(";admin=true")
+
+	tag, _ := mac(msg)
+	f, err := LengthExtensionAttack(sha1.Extender{}, verify, msg, tag, extension, 0, 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if f.KeyLen != len(key) {
+		t.Fatalf("Expected key length %d, but got %d", len(key), f.KeyLen)
+	}
+
+	if _, err := LengthExtensionAttack(sha1.Extender{}, verify, msg, tag, extension, 0, 20); err != NoForgeryFoundErr {
+		t.Fatalf("Expected error %s, but got %s", NoForgeryFoundErr, err)
+	}
+}
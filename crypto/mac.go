@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"crypto/subtle"
+	"hash"
+	"time"
+)
+
+// MacOracle returns the MAC of a message under a secret key
+type MacOracle func(msg []byte) ([]byte, error)
+
+// VerifyOracle reports whether mac is a valid MAC of msg under a secret key
+type VerifyOracle func(msg []byte, mac []byte) (bool, error)
+
+type Mac func(key, msg []byte) []byte
+
+// Comparator reports whether two MACs are equal
+type Comparator func(expected, actual []byte) bool
+
+// SecretPrefix returns the MAC that hashes the concatenation of key and message
+func SecretPrefix(h func() hash.Hash) Mac {
+	return func(key, msg []byte) []byte {
+		d := h()
+		d.Write(key)
+		d.Write(msg)
+		return d.Sum(nil)
+	}
+}
+
+// Hmac returns HMAC as specified in RFC 2104
+func Hmac(h func() hash.Hash) Mac {
+	return func(key, msg []byte) []byte {
+		d := h()
+		bsize := d.BlockSize()
+		if len(key) > bsize {
+			d.Write(key)
+			key = d.Sum(nil)
+			d.Reset()
+		}
+		padded := make([]byte, bsize)
+		copy(padded, key)
+
+		d.Write(XorSingle(padded, 0x36))
+		d.Write(msg)
+		inner := d.Sum(nil)
+
+		d.Reset()
+		d.Write(XorSingle(padded, 0x5c))
+		d.Write(inner)
+		return d.Sum(nil)
+	}
+}
+
+// ConstantTimeCompare compares MACs in time that only depends on their length
+func ConstantTimeCompare(expected, actual []byte) bool {
+	return subtle.ConstantTimeCompare(expected, actual) == 1
+}
+
+// InsecureCompare returns a comparator that exits at the first differing byte and sleeps for delay after every equal byte
+func InsecureCompare(delay time.Duration) Comparator {
+	return func(expected, actual []byte) bool {
+		if len(expected) != len(actual) {
+			return false
+		}
+		for i := range expected {
+			if expected[i] != actual[i] {
+				return false
+			}
+			time.Sleep(delay)
+		}
+		return true
+	}
+}
+
+// Oracles returns oracles that compute and verify MACs under the given key
+func (m Mac) Oracles(key []byte, compare Comparator) (MacOracle, VerifyOracle) {
+	mac := func(msg []byte) ([]byte, error) {
+		return m(key, msg), nil
+	}
+	verify := func(msg []byte, tag []byte) (bool, error) {
+		return compare(m(key, msg), tag), nil
+	}
+	return mac, verify
+}
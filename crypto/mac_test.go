@@ -0,0 +1,121 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	stdsha1 "crypto/sha1"
+	"github.com/kdhageman/go-cryptopals/crypto/md4"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestSecretPrefix(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	msg := []byte("some message")
+
+	expected := sha1.Sum(append(append([]byte{}, key...), msg...))
+	actual := SecretPrefix(sha1.New)(key, msg)
+	if !bytes.Equal(expected[:], actual) {
+		t.Fatalf("Expected MAC %x, but got %x", expected, actual)
+	}
+}
+
+func TestHmac(t *testing.T) {
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{
+			name: "Short key",
+			key:  []byte("key"),
+		},
+		{
+			name: "Block sized key",
+			key:  bytes.Repeat([]byte{0xaa}, 64),
+		},
+		{
+			name: "Long key",
+			key:  bytes.Repeat([]byte{0xaa}, 100),
+		},
+	}
+	msg := []byte("The quick brown fox jumps over the lazy dog")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := hmac.New(stdsha1.New, tt.key)
+			h.Write(msg)
+			expected := h.Sum(nil)
+
+			for _, actual := range [][]byte{Hmac(sha1.New)(tt.key, msg), Hmac(stdsha1.New)(tt.key, msg)} {
+				if !bytes.Equal(expected, actual) {
+					t.Fatalf("Expected HMAC %x, but got %x", expected, actual)
+				}
+			}
+
+			h = hmac.New(md4.New, tt.key)
+			h.Write(msg)
+			if expected, actual := h.Sum(nil), Hmac(md4.New)(tt.key, msg); !bytes.Equal(expected, actual) {
+				t.Fatalf("Expected HMAC %x, but got %x", expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []byte
+		b        []byte
+		expected bool
+	}{
+		{
+			name:     "Equal",
+			a:        []byte{1, 2, 3},
+			b:        []byte{1, 2, 3},
+			expected: true,
+		},
+		{
+			name:     "Different",
+			a:        []byte{1, 2, 3},
+			b:        []byte{1, 2, 4},
+			expected: false,
+		},
+		{
+			name:     "Different length",
+			a:        []byte{1, 2, 3},
+			b:        []byte{1, 2},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, compare := range []Comparator{ConstantTimeCompare, InsecureCompare(0)} {
+				if actual := compare(tt.a, tt.b); actual != tt.expected {
+					t.Fatalf("Expected comparison to be %t, but got %t", tt.expected, actual)
+				}
+			}
+		})
+	}
+
+	start := time.Now()
+	InsecureCompare(5*time.Millisecond)([]byte{1, 2, 3, 4}, []byte{1, 2, 0, 0})
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("Expected insecure compare to take at least %s, but took %s", 10*time.Millisecond, elapsed)
+	}
+}
+
+func TestOracles(t *testing.T) {
+	mac, verify := Hmac(sha1.New).Oracles(RandomKey(16), ConstantTimeCompare)
+	msg := []byte("some message")
+
+	tag, err := mac(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ok, _ := verify(msg, tag); !ok {
+		t.Fatalf("Expected MAC to verify")
+	}
+	if ok, _ := verify(append(msg, '!'), tag); ok {
+		t.Fatalf("Expected MAC of different message not to verify")
+	}
+}
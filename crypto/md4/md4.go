@@ -0,0 +1,134 @@
+// Package md4 implements MD4 with access to its internal state, for length extension attacks and collisions.
+package md4
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto/internal/md"
+	"hash"
+	"math/bits"
+)
+
+const (
+	Size      = 16
+	BlockSize = md.BlockSize
+)
+
+var (
+	InitialState = [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+
+	InvalidDigestSizeErr = errors.New("digest must be 16 bytes")
+	InvalidLengthErr     = md.InvalidLengthErr
+)
+
+// New returns an MD4 hash starting from the standard initial state
+func New() hash.Hash {
+	return NewFromState(InitialState, 0)
+}
+
+// NewFromState returns an MD4 hash that chains from h. The length, which includes the glue padding of the
+// forged prefix, ends up little endian in the final padding, so it must match what the server hashed.
+func NewFromState(h [4]uint32, length uint64) hash.Hash {
+	return md.New(h[:], length, binary.LittleEndian, func(h []uint32, p []byte) {
+		var s [4]uint32
+		copy(s[:], h)
+		Block(&s, p)
+		copy(h, s[:])
+	})
+}
+
+// NewFromDigest returns an MD4 hash that resumes from a published digest of a message of length bytes
+// after padding
+func NewFromDigest(sum []byte, length uint64) (hash.Hash, error) {
+	h, err := State(sum)
+	if err != nil {
+		return nil, err
+	}
+	if length%BlockSize != 0 {
+		return nil, InvalidLengthErr
+	}
+	return NewFromState(h, length), nil
+}
+
+// State reads A, B, C and D back from a digest, in which MD4 stores each of them little endian
+func State(sum []byte) ([4]uint32, error) {
+	var h [4]uint32
+	if len(sum) != Size {
+		return h, InvalidDigestSizeErr
+	}
+	md.Decode(h[:], sum, binary.LittleEndian)
+	return h, nil
+}
+
+// Pad returns the MD4 padding for a message of the given length in bytes, which ends in the 64-bit
+// little endian bit length (RFC 1320, section 3.2)
+func Pad(length uint64) []byte {
+	return md.Pad(length, binary.LittleEndian)
+}
+
+func Sum(data []byte) [Size]byte {
+	var res [Size]byte
+	h := New()
+	h.Write(data)
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+var (
+	shifts1 = [4]int{3, 7, 11, 19}
+	shifts2 = [4]int{3, 5, 9, 13}
+	shifts3 = [4]int{3, 9, 11, 15}
+
+	order2 = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	order3 = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+func F(x, y, z uint32) uint32 {
+	return x&y | ^x&z
+}
+
+func G(x, y, z uint32) uint32 {
+	return x&y | x&z | y&z
+}
+
+func H(x, y, z uint32) uint32 {
+	return x ^ y ^ z
+}
+
+// Block applies the MD4 compression function to a single 64-byte block
+func Block(h *[4]uint32, p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+
+	a, b, c, d := h[0], h[1], h[2], h[3]
+	for i := 0; i < 16; i++ {
+		a = bits.RotateLeft32(a+F(b, c, d)+x[i], shifts1[i%4])
+		a, b, c, d = d, a, b, c
+	}
+	for i := 0; i < 16; i++ {
+		a = bits.RotateLeft32(a+G(b, c, d)+x[order2[i]]+0x5a827999, shifts2[i%4])
+		a, b, c, d = d, a, b, c
+	}
+	for i := 0; i < 16; i++ {
+		a = bits.RotateLeft32(a+H(b, c, d)+x[order3[i]]+0x6ed9eba1, shifts3[i%4])
+		a, b, c, d = d, a, b, c
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+}
+
+// Extender exposes the functions needed for length extension attacks on MD4
+type Extender struct{}
+
+func (Extender) Pad(length uint64) []byte {
+	return Pad(length)
+}
+
+func (Extender) FromDigest(sum []byte, length uint64) (hash.Hash, error) {
+	return NewFromDigest(sum, length)
+}
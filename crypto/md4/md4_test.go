@@ -0,0 +1,66 @@
+package md4
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from RFC 1320, appendix A.5
+func TestSum(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "043f8582f241db351ce627e153e7f0e4"},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "e33b4ddc9c38f2199c3e7b164fcc0536"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual := Sum([]byte(tt.input))
+			if hex.EncodeToString(actual[:]) != tt.expected {
+				t.Fatalf("Expected digest %s, but got %x", tt.expected, actual)
+			}
+
+			// writing byte by byte must give the same result
+			h := New()
+			for i := range tt.input {
+				h.Write([]byte{tt.input[i]})
+			}
+			if sum := h.Sum(nil); !bytes.Equal(sum, actual[:]) {
+				t.Fatalf("Expected digest %x, but got %x", actual, sum)
+			}
+		})
+	}
+}
+
+func TestNewFromDigest(t *testing.T) {
+	msg := []byte("some kind of somewhat long plain text!")
+	extension := []byte(";admin=true")
+	sum := Sum(msg)
+
+	glue := Pad(uint64(len(msg)))
+	h, err := NewFromDigest(sum[:], uint64(len(msg)+len(glue)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	h.Write(extension)
+
+	full := append(append(append([]byte{}, msg...), glue...), extension...)
+	expected := Sum(full)
+	if actual := h.Sum(nil); !bytes.Equal(expected[:], actual) {
+		t.Fatalf("Expected digest %x, but got %x", expected, actual)
+	}
+
+	if _, err := NewFromDigest(sum[:15], 64); err != InvalidDigestSizeErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidDigestSizeErr, err)
+	}
+	if _, err := NewFromDigest(sum[:], 65); err != InvalidLengthErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidLengthErr, err)
+	}
+}
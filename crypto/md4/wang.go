@@ -0,0 +1,328 @@
+package md4
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	round2Const = 0x5a827999
+)
+
+// ConditionKind is the relation a state bit must satisfy
+type ConditionKind int
+
+const (
+	Zero = ConditionKind(iota)
+	One
+	// Equal requires the bit to equal the same bit of another state variable
+	Equal
+	// NotEqual requires the bit to differ from the same bit of another state variable
+	NotEqual
+)
+
+// Condition is a sufficient condition on a bit of an intermediate state variable of MD4, in the notation of
+// "Cryptanalysis of the Hash Functions MD4 and RIPEMD" by Wang et al.: variables are named a1, d1, c1, b1, a2, ...
+// after the step that computes them, and bits are numbered from 1 to 32.
+type Condition struct {
+	Var  string
+	Bit  int
+	Kind ConditionKind
+	Ref  string
+}
+
+func (c Condition) String() string {
+	switch c.Kind {
+	case Zero:
+		return fmt.Sprintf("%s,%d = 0", c.Var, c.Bit)
+	case One:
+		return fmt.Sprintf("%s,%d = 1", c.Var, c.Bit)
+	case Equal:
+		return fmt.Sprintf("%s,%d = %s,%d", c.Var, c.Bit, c.Ref, c.Bit)
+	default:
+		return fmt.Sprintf("%s,%d != %s,%d", c.Var, c.Bit, c.Ref, c.Bit)
+	}
+}
+
+var (
+	// Round1Conditions are the conditions on the first round of the collision differential (table 6 of the paper)
+	Round1Conditions = []Condition{
+		{"a1", 7, Equal, "b0"},
+		{"d1", 7, Zero, ""}, {"d1", 8, Equal, "a1"}, {"d1", 11, Equal, "a1"},
+		{"c1", 7, One, ""}, {"c1", 8, One, ""}, {"c1", 11, Zero, ""}, {"c1", 26, Equal, "d1"},
+		{"b1", 7, One, ""}, {"b1", 8, Zero, ""}, {"b1", 11, Zero, ""}, {"b1", 26, Zero, ""},
+		{"a2", 8, One, ""}, {"a2", 11, One, ""}, {"a2", 26, Zero, ""}, {"a2", 14, Equal, "b1"},
+		{"d2", 14, Zero, ""}, {"d2", 19, Equal, "a2"}, {"d2", 20, Equal, "a2"}, {"d2", 21, Equal, "a2"}, {"d2", 22, Equal, "a2"}, {"d2", 26, One, ""},
+		{"c2", 13, Equal, "d2"}, {"c2", 14, Zero, ""}, {"c2", 15, Equal, "d2"}, {"c2", 19, Zero, ""}, {"c2", 20, Zero, ""}, {"c2", 21, One, ""}, {"c2", 22, Zero, ""},
+		{"b2", 13, One, ""}, {"b2", 14, One, ""}, {"b2", 15, Zero, ""}, {"b2", 17, Equal, "c2"}, {"b2", 19, Zero, ""}, {"b2", 20, Zero, ""}, {"b2", 21, Zero, ""}, {"b2", 22, Zero, ""},
+		{"a3", 13, One, ""}, {"a3", 14, One, ""}, {"a3", 15, One, ""}, {"a3", 17, Zero, ""}, {"a3", 19, Zero, ""}, {"a3", 20, Zero, ""}, {"a3", 21, Zero, ""}, {"a3", 23, Equal, "b2"}, {"a3", 22, One, ""}, {"a3", 26, Equal, "b2"},
+		{"d3", 13, One, ""}, {"d3", 14, One, ""}, {"d3", 15, One, ""}, {"d3", 17, Zero, ""}, {"d3", 20, Zero, ""}, {"d3", 21, One, ""}, {"d3", 22, One, ""}, {"d3", 23, Zero, ""}, {"d3", 26, One, ""}, {"d3", 30, Equal, "a3"},
+		{"c3", 17, One, ""}, {"c3", 20, Zero, ""}, {"c3", 21, Zero, ""}, {"c3", 22, Zero, ""}, {"c3", 23, Zero, ""}, {"c3", 26, Zero, ""}, {"c3", 30, One, ""}, {"c3", 32, Equal, "d3"},
+		{"b3", 20, Zero, ""}, {"b3", 21, One, ""}, {"b3", 22, One, ""}, {"b3", 23, Equal, "c3"}, {"b3", 26, One, ""}, {"b3", 30, Zero, ""}, {"b3", 32, Zero, ""},
+		{"a4", 23, Zero, ""}, {"a4", 26, Zero, ""}, {"a4", 27, Equal, "b3"}, {"a4", 29, Equal, "b3"}, {"a4", 30, One, ""}, {"a4", 32, Zero, ""},
+		{"d4", 23, Zero, ""}, {"d4", 26, Zero, ""}, {"d4", 27, One, ""}, {"d4", 29, One, ""}, {"d4", 30, Zero, ""}, {"d4", 32, One, ""},
+		{"c4", 19, Equal, "d4"}, {"c4", 23, One, ""}, {"c4", 26, One, ""}, {"c4", 27, Zero, ""}, {"c4", 29, Zero, ""}, {"c4", 30, Zero, ""},
+		{"b4", 19, Zero, ""}, {"b4", 26, Equal, "c4"}, {"b4", 27, One, ""}, {"b4", 29, One, ""}, {"b4", 30, Zero, ""},
+	}
+
+	// Round2Conditions are the conditions on the second round of the collision differential
+	Round2Conditions = []Condition{
+		{"a5", 19, Equal, "c4"}, {"a5", 26, One, ""}, {"a5", 27, Zero, ""}, {"a5", 29, One, ""}, {"a5", 32, One, ""},
+		{"d5", 19, Equal, "a5"}, {"d5", 26, Equal, "b4"}, {"d5", 27, Equal, "b4"}, {"d5", 29, Equal, "b4"}, {"d5", 32, Equal, "b4"},
+		{"c5", 26, Equal, "d5"}, {"c5", 27, Equal, "d5"}, {"c5", 29, Equal, "d5"}, {"c5", 30, Equal, "d5"}, {"c5", 32, Equal, "d5"},
+		{"b5", 29, Equal, "c5"}, {"b5", 30, One, ""}, {"b5", 32, Zero, ""},
+		{"a6", 29, One, ""}, {"a6", 32, One, ""},
+		{"d6", 29, Equal, "b5"},
+		{"c6", 29, Equal, "d6"}, {"c6", 30, NotEqual, "d6"}, {"c6", 32, NotEqual, "d6"},
+	}
+)
+
+// stepIndex returns the index of a state variable in the trace: a0, d0, c0, b0 are 0 to 3, and the variable computed
+// by step i is i + 4
+func stepIndex(v string) int {
+	var letter byte
+	var n int
+	fmt.Sscanf(v, "%c%d", &letter, &n)
+	pos := map[byte]int{'a': 0, 'd': 1, 'c': 2, 'b': 3}[letter]
+	return n*4 + pos
+}
+
+// compiled is a condition with the variables resolved to trace indices
+type compiled struct {
+	kind ConditionKind
+	v    int
+	ref  int
+	mask uint32
+}
+
+func compile(c Condition) compiled {
+	res := compiled{
+		kind: c.Kind,
+		v:    stepIndex(c.Var),
+		mask: 1 << uint(c.Bit-1),
+	}
+	if c.Kind == Equal || c.Kind == NotEqual {
+		res.ref = stepIndex(c.Ref)
+	}
+	return res
+}
+
+// holds reports whether the condition holds in the trace
+func (c compiled) holds(t []uint32) bool {
+	bit := t[c.v] & c.mask
+	switch c.kind {
+	case Zero:
+		return bit == 0
+	case One:
+		return bit != 0
+	case Equal:
+		return bit == t[c.ref]&c.mask
+	default:
+		return bit != t[c.ref]&c.mask
+	}
+}
+
+// fix returns the value of the condition's variable changed such that the condition holds
+func (c compiled) fix(v uint32, t []uint32) uint32 {
+	switch c.kind {
+	case Zero:
+		return v &^ c.mask
+	case One:
+		return v | c.mask
+	case Equal:
+		return v ^ (v^t[c.ref])&c.mask
+	default:
+		return v ^ (v^^t[c.ref])&c.mask
+	}
+}
+
+// trace returns the state variables of the first two rounds of MD4 for the message words, starting from the IV
+func trace(h [4]uint32, x *[16]uint32) []uint32 {
+	t := make([]uint32, 36)
+	t[0], t[1], t[2], t[3] = h[0], h[3], h[2], h[1]
+	for i := 0; i < 16; i++ {
+		t[i+4] = step1(t, i, x[i])
+	}
+	for i := 16; i < 32; i++ {
+		t[i+4] = step2(t, i, x[order2[i-16]])
+	}
+	return t
+}
+
+func step1(t []uint32, i int, x uint32) uint32 {
+	return bits.RotateLeft32(t[i]+F(t[i+3], t[i+2], t[i+1])+x, shifts1[i%4])
+}
+
+func step2(t []uint32, i int, x uint32) uint32 {
+	return bits.RotateLeft32(t[i]+G(t[i+3], t[i+2], t[i+1])+x+round2Const, shifts2[i%4])
+}
+
+// word1 returns the message word for which step i of the first round computes v
+func word1(t []uint32, i int, v uint32) uint32 {
+	return bits.RotateLeft32(v, -shifts1[i%4]) - t[i] - F(t[i+3], t[i+2], t[i+1])
+}
+
+// word2 returns the message word for which step i of the second round computes v
+func word2(t []uint32, i int, v uint32) uint32 {
+	return bits.RotateLeft32(v, -shifts2[i%4]) - t[i] - G(t[i+3], t[i+2], t[i+1]) - round2Const
+}
+
+// conditionsByStep compiles the conditions and groups them by the index of the variable they constrain
+func conditionsByStep(conds []Condition) [][]compiled {
+	res := make([][]compiled, 36)
+	for _, c := range conds {
+		cc := compile(c)
+		res[cc.v] = append(res[cc.v], cc)
+	}
+	return res
+}
+
+func compileAll(conds []Condition) []compiled {
+	var res []compiled
+	for _, c := range conds {
+		res = append(res, compile(c))
+	}
+	return res
+}
+
+var (
+	round1ByStep = conditionsByStep(Round1Conditions)
+	round2ByStep = conditionsByStep(Round2Conditions)
+	round1       = compileAll(Round1Conditions)
+)
+
+func allHold(conds []compiled, t []uint32) bool {
+	for _, c := range conds {
+		if !c.holds(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// modify applies message modification to the words, such that all first round conditions and most of the
+// conditions on a5, d5 and c5 hold
+func modify(x *[16]uint32) {
+	h := InitialState
+	t := trace(h, x)
+
+	// single-step modification: every first round variable is computed directly from its message word
+	for i := 0; i < 16; i++ {
+		v := step1(t, i, x[i])
+		for _, c := range round1ByStep[i+4] {
+			v = c.fix(v, t)
+		}
+		t[i+4] = v
+		x[i] = word1(t, i, v)
+	}
+
+	// multi-step modification: a second round variable is corrected through its message word, after which the next
+	// four message words are recomputed to leave the rest of the first round untouched
+	for i := 16; i < 19; i++ {
+		t = trace(h, x)
+		w := order2[i-16]
+		v := step2(t, i, x[w])
+		for _, c := range round2ByStep[i+4] {
+			v = c.fix(v, t)
+		}
+		if v == t[i+4] {
+			continue
+		}
+
+		modified := *x
+		modified[w] = word2(t, i, v)
+		mt := append([]uint32{}, t...)
+		mt[w+4] = step1(mt, w, modified[w])
+		for j := w + 1; j <= w+4; j++ {
+			modified[j] = word1(mt, j, t[j+4])
+		}
+		if mt = trace(h, &modified); allHold(round1, mt) {
+			*x = modified
+		}
+	}
+}
+
+// delta returns the colliding counterpart of the message words: m1 + 2^31, m2 + 2^31 - 2^28, m12 - 2^16
+func delta(x [16]uint32) [16]uint32 {
+	x[1] += 1 << 31
+	x[2] += 1<<31 - 1<<28
+	x[12] -= 1 << 16
+	return x
+}
+
+func words(block []byte) [16]uint32 {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	return x
+}
+
+func fromWords(x [16]uint32) []byte {
+	res := make([]byte, BlockSize)
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(res[4*i:], w)
+	}
+	return res
+}
+
+// Delta returns the block that differs from the block by the collision differential
+func Delta(block []byte) []byte {
+	return fromWords(delta(words(block)))
+}
+
+// CollisionResult is a pair of colliding single block messages
+type CollisionResult struct {
+	A, B []byte
+	// Attempts is the number of modified messages that were tried
+	Attempts int
+}
+
+// Collision finds two different blocks with the same MD4 digest using the differential of Wang et al.
+// Random messages are modified to satisfy the first round conditions and most of the second round, after which the
+// remaining conditions are left to chance.
+func Collision(r *rand.Rand) CollisionResult {
+	for attempts := 1; ; attempts++ {
+		var x [16]uint32
+		for i := range x {
+			x[i] = r.Uint32()
+		}
+		modify(&x)
+
+		h1, h2 := InitialState, InitialState
+		a, b := fromWords(x), fromWords(delta(x))
+		Block(&h1, a)
+		Block(&h2, b)
+		if h1 == h2 {
+			return CollisionResult{a, b, attempts}
+		}
+	}
+}
+
+// Check is a condition together with whether it holds for a block
+type Check struct {
+	Condition
+	Holds bool
+}
+
+func (c Check) String() string {
+	if c.Holds {
+		return fmt.Sprintf("%s holds", c.Condition)
+	}
+	return fmt.Sprintf("%s fails", c.Condition)
+}
+
+// Explain checks all first and second round conditions for the block
+func Explain(block []byte) []Check {
+	x := words(block)
+	t := trace(InitialState, &x)
+	var res []Check
+	for _, conds := range [][]Condition{Round1Conditions, Round2Conditions} {
+		for _, c := range conds {
+			res = append(res, Check{c, compile(c).holds(t)})
+		}
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+package md4
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestStepIndex(t *testing.T) {
+	tests := map[string]int{
+		"a0": 0, "d0": 1, "c0": 2, "b0": 3,
+		"a1": 4, "b4": 19, "a5": 20, "c6": 26,
+	}
+	for v, expected := range tests {
+		if actual := stepIndex(v); actual != expected {
+			t.Fatalf("Expected index %d for %s, but got %d", expected, v, actual)
+		}
+	}
+}
+
+func TestConditionString(t *testing.T) {
+	tests := []struct {
+		c        Condition
+		expected string
+	}{
+		{Condition{"d1", 7, Zero, ""}, "d1,7 = 0"},
+		{Condition{"c1", 8, One, ""}, "c1,8 = 1"},
+		{Condition{"a1", 7, Equal, "b0"}, "a1,7 = b0,7"},
+		{Condition{"c6", 30, NotEqual, "d6"}, "c6,30 != d6,30"},
+	}
+	for _, tt := range tests {
+		if actual := tt.c.String(); actual != tt.expected {
+			t.Fatalf("Expected %q, but got %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestDelta(t *testing.T) {
+	block := make([]byte, BlockSize)
+	d := Delta(block)
+	x := words(d)
+	for i, w := range x {
+		var expected uint32
+		switch i {
+		case 1:
+			expected = 1 << 31
+		case 2:
+			expected = 1<<31 - 1<<28
+		case 12:
+			expected = 0xffff0000
+		}
+		if w != expected {
+			t.Fatalf("Expected word %d to be %x, but got %x", i, expected, w)
+		}
+	}
+}
+
+func TestModify(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x [16]uint32
+		for j := range x {
+			x[j] = r.Uint32()
+		}
+		modify(&x)
+		for _, c := range Explain(fromWords(x))[:len(Round1Conditions)] {
+			if !c.Holds {
+				t.Fatalf("Expected first round condition to hold: %s", c)
+			}
+		}
+	}
+}
+
+func TestCollision(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			res := Collision(rand.New(rand.NewSource(seed)))
+			if bytes.Equal(res.A, res.B) {
+				t.Fatalf("Expected different messages")
+			}
+			if !bytes.Equal(Delta(res.A), res.B) {
+				t.Fatalf("Expected messages to differ by the differential")
+			}
+			if Sum(res.A) != Sum(res.B) {
+				t.Fatalf("Expected equal digests, but got %x and %x", Sum(res.A), Sum(res.B))
+			}
+			if res.Attempts == 0 {
+				t.Fatalf("Expected attempts to be counted")
+			}
+			// the conditions are sufficient but not necessary, so only the first round is guaranteed to hold
+			for _, c := range Explain(res.A)[:len(Round1Conditions)] {
+				if !c.Holds {
+					t.Fatalf("Expected first round condition to hold: %s", c)
+				}
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	checks := Explain(make([]byte, BlockSize))
+	if len(checks) != len(Round1Conditions)+len(Round2Conditions) {
+		t.Fatalf("Expected %d checks, but got %d", len(Round1Conditions)+len(Round2Conditions), len(checks))
+	}
+	failed := 0
+	for _, c := range checks {
+		if !c.Holds {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Fatalf("Expected some conditions to fail for the zero block")
+	}
+}
@@ -0,0 +1,118 @@
+package mdhash
+
+import (
+	"bytes"
+	"errors"
+)
+
+var (
+	NoCascadeCollisionErr = errors.New("failed to find a collision in the cascade")
+)
+
+// Converge finds blocks a and b such that C(s1, a) = C(s2, b), and returns them with the common state.
+// With s1 = s2 this is a plain birthday collision of the compression function.
+func (h *Hash) Converge(s1, s2 []byte) ([]byte, []byte, []byte) {
+	seen1 := map[string][]byte{}
+	seen2 := map[string][]byte{}
+	if bytes.Equal(s1, s2) {
+		// both halves of the search can collide with each other
+		seen2 = seen1
+	}
+	for {
+		a := randomBlock()
+		out := string(h.Compress(s1, a))
+		if b, ok := seen2[out]; ok && !bytes.Equal(a, b) {
+			return a, b, []byte(out)
+		}
+		seen1[out] = a
+
+		b := randomBlock()
+		out = string(h.Compress(s2, b))
+		if a, ok := seen1[out]; ok && !bytes.Equal(a, b) {
+			return a, b, []byte(out)
+		}
+		seen2[out] = b
+	}
+}
+
+// Multicollision is a set of 2^n messages of n blocks that all reach the same state, as found by Joux:
+// every pair of blocks collides from the state that the previous pairs lead to.
+type Multicollision struct {
+	Pairs [][2][]byte
+	State []byte
+	// Calls is the number of compression function calls spent finding the collisions
+	Calls uint64
+}
+
+// Multicollision returns a multicollision of 2^n messages starting from the state
+func (h *Hash) Multicollision(state []byte, n int) *Multicollision {
+	m := &Multicollision{
+		State: state,
+	}
+	for i := 0; i < n; i++ {
+		m.Extend(h)
+	}
+	return m
+}
+
+// Extend doubles the number of colliding messages by appending another pair of colliding blocks
+func (m *Multicollision) Extend(h *Hash) {
+	start := h.Calls()
+	a, b, state := h.Converge(m.State, m.State)
+	m.Pairs = append(m.Pairs, [2][]byte{a, b})
+	m.State = state
+	m.Calls += h.Calls() - start
+}
+
+// Len returns the number of colliding messages
+func (m *Multicollision) Len() uint64 {
+	return 1 << uint(len(m.Pairs))
+}
+
+// Message returns the i-th colliding message, in which bit j of i selects the block of the j-th pair
+func (m *Multicollision) Message(i uint64) []byte {
+	var res []byte
+	for j, p := range m.Pairs {
+		res = append(res, p[(i>>uint(j))&1]...)
+	}
+	return res
+}
+
+// CascadeSum returns f(msg) || g(msg)
+func CascadeSum(f, g *Hash, msg []byte) []byte {
+	return append(f.Sum(msg), g.Sum(msg)...)
+}
+
+// CascadeResult is a collision of f || g with the work it took
+type CascadeResult struct {
+	A, B   []byte
+	FCalls uint64
+	GCalls uint64
+}
+
+// CascadeCollision finds a collision of f(x) || g(x), which costs little more than a collision of the stronger hash g:
+// a Joux multicollision of f with 2^(b/2) messages, where b is the size of g in bits, likely contains a collision of g.
+func CascadeCollision(f, g *Hash) (CascadeResult, error) {
+	fStart, gStart := f.Calls(), g.Calls()
+	n := g.Size() * 8 / 2
+	m := f.Multicollision(f.Iv(), n)
+	// the multicollision is extended until it contains a collision of g, which usually happens within a few blocks
+	for len(m.Pairs) <= 2*n {
+		seen := map[string]uint64{}
+		for i := uint64(0); i < m.Len(); i++ {
+			msg := m.Message(i)
+			d := string(g.Sum(msg))
+			if j, ok := seen[d]; ok {
+				return CascadeResult{
+					A:      m.Message(j),
+					B:      msg,
+					FCalls: f.Calls() - fStart,
+					GCalls: g.Calls() - gStart,
+				}, nil
+			}
+			seen[d] = i
+		}
+		m.Extend(f)
+	}
+	return CascadeResult{}, NoCascadeCollisionErr
+}
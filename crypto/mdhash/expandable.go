@@ -0,0 +1,111 @@
+package mdhash
+
+import (
+	"errors"
+)
+
+var (
+	InvalidLengthErr   = errors.New("expandable message cannot produce the requested length")
+	MessageTooShortErr = errors.New("message is too short for the expandable message")
+)
+
+type piece struct {
+	short []byte
+	long  []byte
+}
+
+// Expandable is an expandable message: a set of messages of k to k + 2^k - 1 blocks that all reach the same state.
+// Piece i consists of a single block that collides with 2^(k-1-i) dummy blocks followed by a block.
+type Expandable struct {
+	k      int
+	pieces []piece
+	State  []byte
+	// Calls is the number of compression function calls spent building the message
+	Calls uint64
+}
+
+// NewExpandable builds an expandable message from the state
+func NewExpandable(h *Hash, state []byte, k int) *Expandable {
+	start := h.Calls()
+	e := &Expandable{
+		k: k,
+	}
+	for i := 0; i < k; i++ {
+		dummy := make([]byte, BlockSize<<uint(k-1-i))
+		dummyState, _ := h.Iterate(state, dummy)
+		a, b, next := h.Converge(state, dummyState)
+		e.pieces = append(e.pieces, piece{
+			short: a,
+			long:  append(dummy, b...),
+		})
+		state = next
+	}
+	e.State = state
+	e.Calls = h.Calls() - start
+	return e
+}
+
+// Message returns the message of the given number of blocks
+func (e *Expandable) Message(blocks int) ([]byte, error) {
+	extra := blocks - e.k
+	if extra < 0 || extra >= 1<<uint(e.k) {
+		return nil, InvalidLengthErr
+	}
+	var res []byte
+	for i, p := range e.pieces {
+		if extra&(1<<uint(e.k-1-i)) != 0 {
+			res = append(res, p.long...)
+		} else {
+			res = append(res, p.short...)
+		}
+	}
+	return res, nil
+}
+
+// PreimageResult is a second preimage with the work it took
+type PreimageResult struct {
+	Message []byte
+	Calls   uint64
+}
+
+// SecondPreimage finds a different message with the same hash as the block aligned message, following Kelsey and
+// Schneier. A bridge block leads the final state of an expandable message to one of the intermediate states of the
+// message, after which the expandable message is sized such that the length padding is the same.
+func SecondPreimage(h *Hash, msg []byte, k int) (PreimageResult, error) {
+	if len(msg)%BlockSize != 0 {
+		return PreimageResult{}, UnalignedMessageErr
+	}
+	n := len(msg) / BlockSize
+	if n < k+1 {
+		return PreimageResult{}, MessageTooShortErr
+	}
+	start := h.Calls()
+
+	// the state after j blocks can be reached with an expandable message of j - 1 blocks and a bridge block
+	targets := map[string]int{}
+	state := h.Iv()
+	for j := 1; j <= n && j <= k+1<<uint(k); j++ {
+		state = h.Compress(state, msg[(j-1)*BlockSize:j*BlockSize])
+		if j >= k+1 {
+			targets[string(state)] = j
+		}
+	}
+
+	e := NewExpandable(h, h.Iv(), k)
+	for {
+		bridge := randomBlock()
+		j, ok := targets[string(h.Compress(e.State, bridge))]
+		if !ok {
+			continue
+		}
+		prefix, err := e.Message(j - 1)
+		if err != nil {
+			return PreimageResult{}, err
+		}
+		res := append(append(prefix, bridge...), msg[j*BlockSize:]...)
+		return PreimageResult{
+			Message: res,
+			Calls:   h.Calls() - start,
+		}, nil
+	}
+}
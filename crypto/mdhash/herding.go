@@ -0,0 +1,83 @@
+package mdhash
+
+// Diamond is a binary tree of colliding blocks that leads 2^k leaf states to a single root state.
+// Committing to the hash of the root allows an attacker to later herd any prefix into it with one glue block.
+type Diamond struct {
+	h      *Hash
+	k      int
+	leaves map[string]int
+	// blocks[l][i] leads node i on level l to its parent
+	blocks [][][]byte
+	root   []byte
+	// Calls is the number of compression function calls spent building the structure
+	Calls uint64
+}
+
+// NewDiamond builds a diamond structure with 2^k random leaf states
+func NewDiamond(h *Hash, k int) *Diamond {
+	start := h.Calls()
+	d := &Diamond{
+		h:      h,
+		k:      k,
+		leaves: map[string]int{},
+	}
+	var states [][]byte
+	for len(states) < 1<<uint(k) {
+		s := randomBlock()[:h.Size()]
+		if _, ok := d.leaves[string(s)]; ok {
+			continue
+		}
+		d.leaves[string(s)] = len(states)
+		states = append(states, s)
+	}
+	for l := 0; l < k; l++ {
+		var blocks, next [][]byte
+		for i := 0; i < len(states); i += 2 {
+			a, b, s := h.Converge(states[i], states[i+1])
+			blocks = append(blocks, a, b)
+			next = append(next, s)
+		}
+		d.blocks = append(d.blocks, blocks)
+		states = next
+	}
+	d.root = states[0]
+	d.Calls = h.Calls() - start
+	return d
+}
+
+// Prediction returns the hash of every message herded from a prefix of the given length, which must be block aligned
+func (d *Diamond) Prediction(prefixLen uint64) ([]byte, error) {
+	return d.h.Finalize(d.root, prefixLen+uint64(d.k+1)*BlockSize)
+}
+
+// HerdResult is a message herded into a diamond structure with the work it took
+type HerdResult struct {
+	Message []byte
+	Calls   uint64
+}
+
+// Herd finds a message that starts with the block aligned prefix and hashes to the prediction. A glue block leads
+// the state after the prefix to one of the leaves, after which the tree leads to the root.
+func (d *Diamond) Herd(prefix []byte) (HerdResult, error) {
+	start := d.h.Calls()
+	state, err := d.h.Iterate(d.h.Iv(), prefix)
+	if err != nil {
+		return HerdResult{}, err
+	}
+	for {
+		glue := randomBlock()
+		i, ok := d.leaves[string(d.h.Compress(state, glue))]
+		if !ok {
+			continue
+		}
+		msg := append(append([]byte{}, prefix...), glue...)
+		for l := 0; l < d.k; l++ {
+			msg = append(msg, d.blocks[l][i]...)
+			i /= 2
+		}
+		return HerdResult{
+			Message: msg,
+			Calls:   d.h.Calls() - start,
+		}, nil
+	}
+}
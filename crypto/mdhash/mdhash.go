@@ -0,0 +1,116 @@
+// Package mdhash implements a deliberately weak Merkle-Damgard hash with a tiny state, and generic attacks on
+// iterated hash functions: Joux multicollisions, herding and second preimages with expandable messages.
+package mdhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/rijndael"
+)
+
+const (
+	// BlockSize is the size of a message block, which is used as AES key
+	BlockSize = rijndael.BlockSize
+)
+
+var (
+	InvalidSizeErr      = errors.New("state size must be between 1 and 16 bytes")
+	InvalidStateErr     = errors.New("state does not match the size of the hash")
+	UnalignedMessageErr = errors.New("message length must be a multiple of the block size")
+)
+
+// Hash is a Merkle-Damgard hash with the compression function C(h, m) = AES_m(h), truncated to the state size.
+// It counts the calls to the compression function, which is the unit of work of all attacks.
+type Hash struct {
+	size  int
+	iv    []byte
+	calls uint64
+}
+
+// New returns a hash with a state of size bytes and the given IV, which is zero when nil
+func New(size int, iv []byte) (*Hash, error) {
+	if size < 1 || size > BlockSize {
+		return nil, InvalidSizeErr
+	}
+	if iv == nil {
+		iv = make([]byte, size)
+	}
+	if len(iv) != size {
+		return nil, InvalidStateErr
+	}
+	return &Hash{
+		size: size,
+		iv:   append([]byte{}, iv...),
+	}, nil
+}
+
+// Size returns the size of the state and digest in bytes
+func (h *Hash) Size() int {
+	return h.size
+}
+
+// Iv returns the initial state
+func (h *Hash) Iv() []byte {
+	return append([]byte{}, h.iv...)
+}
+
+// Calls returns the number of compression function calls so far
+func (h *Hash) Calls() uint64 {
+	return h.calls
+}
+
+// Compress returns the state after processing a single block
+func (h *Hash) Compress(state, block []byte) []byte {
+	h.calls++
+	c, err := rijndael.NewCipher(block)
+	if err != nil {
+		// blocks always have a valid key size
+		panic(err)
+	}
+	var src, dst [BlockSize]byte
+	copy(src[:], state)
+	c.Encrypt(dst[:], src[:])
+	return dst[:h.size]
+}
+
+// Iterate returns the state after processing the blocks, without padding
+func (h *Hash) Iterate(state, blocks []byte) ([]byte, error) {
+	if len(blocks)%BlockSize != 0 {
+		return nil, UnalignedMessageErr
+	}
+	for i := 0; i < len(blocks); i += BlockSize {
+		state = h.Compress(state, blocks[i:i+BlockSize])
+	}
+	return state, nil
+}
+
+// Pad returns the padding of a message of the given length: 0x80, zeroes and the bit length as 64-bit big endian
+func Pad(length uint64) []byte {
+	pad := []byte{0x80}
+	for (length+uint64(len(pad))+8)%BlockSize != 0 {
+		pad = append(pad, 0)
+	}
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], length*8)
+	return append(pad, l[:]...)
+}
+
+// Finalize processes the padding of a block aligned message of the given length, given the state after its blocks
+func (h *Hash) Finalize(state []byte, length uint64) ([]byte, error) {
+	if length%BlockSize != 0 {
+		return nil, UnalignedMessageErr
+	}
+	return h.Iterate(state, Pad(length))
+}
+
+// Sum returns the digest of the message, including the length padding
+func (h *Hash) Sum(msg []byte) []byte {
+	padded := append(append([]byte{}, msg...), Pad(uint64(len(msg)))...)
+	res, _ := h.Iterate(h.iv, padded)
+	return res
+}
+
+func randomBlock() []byte {
+	return crypto.RandomKey(BlockSize)
+}
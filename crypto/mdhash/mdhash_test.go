@@ -0,0 +1,189 @@
+package mdhash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newHash(t *testing.T, size int) *Hash {
+	h, err := New(size, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return h
+}
+
+func TestNew(t *testing.T) {
+	for _, size := range []int{0, 17} {
+		if _, err := New(size, nil); err != InvalidSizeErr {
+			t.Fatalf("Expected error %s, but got %v", InvalidSizeErr, err)
+		}
+	}
+	if _, err := New(2, []byte{1, 2, 3}); err != InvalidStateErr {
+		t.Fatalf("Expected error %s, but got %v", InvalidStateErr, err)
+	}
+}
+
+func TestPad(t *testing.T) {
+	for l := uint64(0); l < 3*BlockSize; l++ {
+		pad := Pad(l)
+		if (l+uint64(len(pad)))%BlockSize != 0 || pad[0] != 0x80 {
+			t.Fatalf("Invalid padding for length %d: %x", l, pad)
+		}
+		if pad[len(pad)-1] != byte(l*8) {
+			t.Fatalf("Expected padding to end with the bit length")
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	h := newHash(t, 2)
+	msg := []byte("an aligned message of 32 bytes!!")
+	digest := h.Sum(msg)
+	if len(digest) != 2 {
+		t.Fatalf("Expected digest of %d bytes, but got %d", 2, len(digest))
+	}
+	if !bytes.Equal(digest, h.Sum(msg)) {
+		t.Fatalf("Expected digest to be deterministic")
+	}
+	state, err := h.Iterate(h.Iv(), msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	final, err := h.Finalize(state, uint64(len(msg)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(digest, final) {
+		t.Fatalf("Expected finalized state %x to equal digest %x", final, digest)
+	}
+	if h.Calls() == 0 {
+		t.Fatalf("Expected compression calls to be counted")
+	}
+}
+
+func TestMulticollision(t *testing.T) {
+	h := newHash(t, 2)
+	m := h.Multicollision(h.Iv(), 5)
+	if m.Len() != 32 {
+		t.Fatalf("Expected %d messages, but got %d", 32, m.Len())
+	}
+	if m.Calls == 0 {
+		t.Fatalf("Expected work to be counted")
+	}
+	expected := h.Sum(m.Message(0))
+	seen := map[string]bool{}
+	for i := uint64(0); i < m.Len(); i++ {
+		msg := m.Message(i)
+		seen[string(msg)] = true
+		if actual := h.Sum(msg); !bytes.Equal(expected, actual) {
+			t.Fatalf("Expected digest %x, but got %x", expected, actual)
+		}
+	}
+	if len(seen) != 32 {
+		t.Fatalf("Expected %d distinct messages, but got %d", 32, len(seen))
+	}
+}
+
+func TestCascadeCollision(t *testing.T) {
+	f := newHash(t, 2)
+	g, err := New(3, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	res, err := CascadeCollision(f, g)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if bytes.Equal(res.A, res.B) {
+		t.Fatalf("Expected different messages")
+	}
+	if a, b := CascadeSum(f, g, res.A), CascadeSum(f, g, res.B); !bytes.Equal(a, b) {
+		t.Fatalf("Expected equal cascade digests, but got %x and %x", a, b)
+	}
+	if res.FCalls == 0 || res.GCalls == 0 {
+		t.Fatalf("Expected work to be counted")
+	}
+}
+
+func TestHerd(t *testing.T) {
+	h := newHash(t, 2)
+	d := NewDiamond(h, 6)
+	prefix := []byte("The winners of the 2026 season: ")
+	prediction, err := d.Prediction(uint64(len(prefix)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	res, err := d.Herd(prefix)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.HasPrefix(res.Message, prefix) {
+		t.Fatalf("Expected message to start with the prefix")
+	}
+	if actual := h.Sum(res.Message); !bytes.Equal(prediction, actual) {
+		t.Fatalf("Expected digest %x, but got %x", prediction, actual)
+	}
+	if d.Calls == 0 || res.Calls == 0 {
+		t.Fatalf("Expected work to be counted")
+	}
+
+	if _, err := d.Herd([]byte("unaligned")); err != UnalignedMessageErr {
+		t.Fatalf("Expected error %s, but got %v", UnalignedMessageErr, err)
+	}
+}
+
+func TestExpandable(t *testing.T) {
+	h := newHash(t, 2)
+	k := 4
+	e := NewExpandable(h, h.Iv(), k)
+	for blocks := k; blocks < k+1<<uint(k); blocks++ {
+		msg, err := e.Message(blocks)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(msg) != blocks*BlockSize {
+			t.Fatalf("Expected %d blocks, but got %d bytes", blocks, len(msg))
+		}
+		state, _ := h.Iterate(h.Iv(), msg)
+		if !bytes.Equal(e.State, state) {
+			t.Fatalf("Expected state %x, but got %x", e.State, state)
+		}
+	}
+	for _, blocks := range []int{k - 1, k + 1<<uint(k)} {
+		if _, err := e.Message(blocks); err != InvalidLengthErr {
+			t.Fatalf("Expected error %s, but got %v", InvalidLengthErr, err)
+		}
+	}
+}
+
+func TestSecondPreimage(t *testing.T) {
+	h := newHash(t, 2)
+	k := 8
+	msg := make([]byte, BlockSize<<uint(k))
+	for i := range msg {
+		msg[i] = byte(i * 7)
+	}
+
+	res, err := SecondPreimage(h, msg, k)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if bytes.Equal(msg, res.Message) {
+		t.Fatalf("Expected a different message")
+	}
+	if len(msg) != len(res.Message) {
+		t.Fatalf("Expected length %d, but got %d", len(msg), len(res.Message))
+	}
+	if expected, actual := h.Sum(msg), h.Sum(res.Message); !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected digest %x, but got %x", expected, actual)
+	}
+	if res.Calls == 0 {
+		t.Fatalf("Expected work to be counted")
+	}
+
+	if _, err := SecondPreimage(h, msg[:BlockSize*k], k); err != MessageTooShortErr {
+		t.Fatalf("Expected error %s, but got %v", MessageTooShortErr, err)
+	}
+}
@@ -0,0 +1,151 @@
+package mersenne
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	chunkSize = 1 << 14
+)
+
+var (
+	NoObservationsErr = errors.New("at least one observed output is required")
+	InvalidRangeErr   = errors.New("lower bound of seed range exceeds upper bound")
+)
+
+// SeedRange is an inclusive range of candidate seeds
+type SeedRange struct {
+	Lower uint32
+	Upper uint32
+}
+
+func (r SeedRange) size() uint64 {
+	return uint64(r.Upper) - uint64(r.Lower) + 1
+}
+
+// TimeRange returns the seeds corresponding to the Unix timestamps in [from, to]
+func TimeRange(from, to time.Time) SeedRange {
+	return SeedRange{
+		Lower: uint32(from.Unix()),
+		Upper: uint32(to.Unix()),
+	}
+}
+
+// FullRange returns the range of all 32-bit seeds
+func FullRange() SeedRange {
+	return SeedRange{
+		Lower: 0,
+		Upper: math.MaxUint32,
+	}
+}
+
+// Match is a seed for which the observed outputs were found, starting at output Offset
+type Match struct {
+	Seed   uint32
+	Offset int
+}
+
+type CrackOpts struct {
+	// MaxOffset is the number of outputs that may have been drawn before the first observed output
+	MaxOffset int
+	// Workers is the number of goroutines used for the search, defaulting to the number of CPUs
+	Workers int
+	// Progress is called with the number of checked seeds after every chunk of seeds
+	Progress func(checked, total uint64)
+}
+
+// CrackSeed returns all seeds in the given range for which the generator produces the observed
+// consecutive outputs, at an offset of at most opts.MaxOffset
+func CrackSeed(observed []int32, r SeedRange, opts CrackOpts) ([]Match, error) {
+	if len(observed) == 0 {
+		return nil, NoObservationsErr
+	}
+	if r.Lower > r.Upper {
+		return nil, InvalidRangeErr
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	total := r.size()
+	chunks := make(chan uint64)
+	go func() {
+		for start := uint64(0); start < total; start += chunkSize {
+			chunks <- start
+		}
+		close(chunks)
+	}()
+
+	var (
+		mu      sync.Mutex
+		matches []Match
+		checked uint64
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mt := &mersenneTwister{
+				state: make([]uint32, n),
+			}
+			outputs := make([]int32, opts.MaxOffset+len(observed))
+			for start := range chunks {
+				end := start + chunkSize
+				if end > total {
+					end = total
+				}
+				for i := start; i < end; i++ {
+					seed := r.Lower + uint32(i)
+					mt.Seed(int(seed))
+					for j := range outputs {
+						outputs[j], _ = mt.Rand()
+					}
+					for _, offset := range findAll(outputs, observed) {
+						mu.Lock()
+						matches = append(matches, Match{seed, offset})
+						mu.Unlock()
+					}
+				}
+
+				// count and report under the lock, so that progress is reported in order
+				mu.Lock()
+				checked += end - start
+				if opts.Progress != nil {
+					opts.Progress(checked, total)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Seed == matches[j].Seed {
+			return matches[i].Offset < matches[j].Offset
+		}
+		return matches[i].Seed < matches[j].Seed
+	})
+	return matches, nil
+}
+
+// findAll returns the offsets in outputs at which the observed sequence starts
+func findAll(outputs []int32, observed []int32) []int {
+	var res []int
+Outer:
+	for offset := 0; offset+len(observed) <= len(outputs); offset++ {
+		for i, v := range observed {
+			if outputs[offset+i] != v {
+				continue Outer
+			}
+		}
+		res = append(res, offset)
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package mersenne
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCrackSeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     uint32
+		offset   int
+		observed int
+		r        SeedRange
+	}{
+		{
+			name:     "First output",
+			seed:     1000123,
+			offset:   0,
+			observed: 1,
+			r:        SeedRange{1000000, 1020000},
+		},
+		{
+			name:     "Later outputs",
+			seed:     1019999,
+			offset:   37,
+			observed: 3,
+			r:        SeedRange{1000000, 1020000},
+		},
+		{
+			name:     "Range at the top of the seed space",
+			seed:     0xffffffff,
+			offset:   5,
+			observed: 2,
+			r:        SeedRange{0xfffff000, 0xffffffff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := New()
+			mt.Seed(int(tt.seed))
+			observed := outputs(mt, tt.offset+tt.observed)[tt.offset:]
+
+			var last uint64
+			opts := CrackOpts{
+				MaxOffset: 50,
+				Progress: func(checked, total uint64) {
+					if checked < last || checked > total {
+						t.Errorf("Unexpected progress %d/%d after %d", checked, total, last)
+					}
+					last = checked
+				},
+			}
+			matches, err := CrackSeed(observed, tt.r, opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(matches) != 1 {
+				t.Fatalf("Expected a single match, but got %v", matches)
+			}
+			expected := Match{tt.seed, tt.offset}
+			if matches[0] != expected {
+				t.Fatalf("Expected match %v, but got %v", expected, matches[0])
+			}
+			if last != tt.r.size() {
+				t.Fatalf("Expected progress to reach %d, but got %d", tt.r.size(), last)
+			}
+		})
+	}
+}
+
+func TestCrackSeedErrors(t *testing.T) {
+	if _, err := CrackSeed(nil, FullRange(), CrackOpts{}); err != NoObservationsErr {
+		t.Fatalf("Expected error %s, but got %s", NoObservationsErr, err)
+	}
+	if _, err := CrackSeed([]int32{1}, SeedRange{2, 1}, CrackOpts{}); err != InvalidRangeErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidRangeErr, err)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	from := time.Unix(1500000000, 0)
+	r := TimeRange(from, from.Add(time.Hour))
+	if r.Lower != 1500000000 || r.Upper != 1500003600 {
+		t.Fatalf("Unexpected range %v", r)
+	}
+}
@@ -4,14 +4,22 @@ import "errors"
 
 const (
 	n = 624
+	m = 397
+
+	upperMask = 0x80000000
+	lowerMask = 0x7fffffff
+	matrixA   = 0x9908b0df
 )
 
 var (
-	NotSeededErr = errors.New("must seed mersenne twister before retrieving random numbers")
+	NotSeededErr        = errors.New("must seed mersenne twister before retrieving random numbers")
+	InvalidStateSizeErr = errors.New("state must consist of exactly 624 words")
+	TooFewOutputsErr    = errors.New("at least 624 consecutive outputs are required to clone a mersenne twister")
 )
 
 type MersenneTwister interface {
 	Rand() (int32, error)
+	Prev() (int32, error)
 	Seed(seed int)
 }
 
@@ -23,16 +31,39 @@ type mersenneTwister struct {
 
 func (mt *mersenneTwister) twist() {
 	for i := 0; i < n; i++ {
-		x := (mt.state[i] & 0x80000000) + (mt.state[(i+1)%n] & 0x7fffffff)
+		x := (mt.state[i] & upperMask) + (mt.state[(i+1)%n] & lowerMask)
 		xA := x >> 1
 		if x%2 != 0 {
-			xA = xA ^ 0x9908b0df
+			xA = xA ^ matrixA
 		}
-		mt.state[i] = mt.state[(i+397)%n] ^ xA
+		mt.state[i] = mt.state[(i+m)%n] ^ xA
 	}
 	mt.index = 0
 }
 
+// invertA returns the input of the twist transformation for which the output is x
+func invertA(xA uint32) uint32 {
+	if xA&upperMask != 0 {
+		return ((xA ^ matrixA) << 1) | 1
+	}
+	return xA << 1
+}
+
+// untwist replaces the state by the state that preceded the last twist.
+// Every word x[k] of the output sequence satisfies x[k+n] = x[k+m] ^ A(upper(x[k]) | lower(x[k+1])),
+// so the upper bit of x[k] and the lower bits of x[k+1] can be recovered from x[k+n] and x[k+m]
+func (mt *mersenneTwister) untwist() {
+	seq := make([]uint32, 2*n)
+	copy(seq[n:], mt.state)
+	for j := n - 1; j >= 0; j-- {
+		upper := invertA(seq[j+n]^seq[j+m]) & upperMask
+		lower := invertA(seq[j+n-1]^seq[j+m-1]) & lowerMask
+		seq[j] = upper | lower
+	}
+	copy(mt.state, seq[:n])
+	mt.index = n
+}
+
 func (mt *mersenneTwister) Seed(seed int) {
 	mt.index = n
 	mt.state[0] = uint32(seed)
@@ -50,22 +81,80 @@ func (mt *mersenneTwister) Rand() (int32, error) {
 		mt.twist()
 	}
 
-	y := uint64(mt.state[mt.index])
+	y := Temper(mt.state[mt.index])
+	mt.index++
+
+	return y, nil
+}
+
+// Prev steps the generator back by one position and returns the output that was last returned by Rand.
+// Calling Rand afterwards yields the same value again.
+func (mt *mersenneTwister) Prev() (int32, error) {
+	if !mt.seeded {
+		return 0, NotSeededErr
+	}
+	if mt.index <= 0 {
+		mt.untwist()
+	}
+	mt.index--
+
+	return Temper(mt.state[mt.index]), nil
+}
+
+func Temper(x uint32) int32 {
+	y := uint64(x)
 	y ^= (y >> 11) & 0xffffffff
 	y ^= (y << 7) & 0x9d2c5680
 	y ^= (y << 15) & 0xefc60000
 	y ^= y >> 18
-	mt.index++
+	return int32(y)
+}
 
-	return int32(y), nil
+func Untemper(v int32) uint32 {
+	y := uint64(v) & 0xffffffff
+	y ^= y >> 18
+	y ^= (y << 15) & 0xefc60000
+	for i := 0; i < 7; i++ {
+		y ^= (y << 7) & 0x9d2c5680
+	}
+	for i := 0; i < 3; i++ {
+		y ^= y >> 11
+	}
+	return uint32(y)
 }
 
-func FromSlice(state []uint32) MersenneTwister {
-	return &mersenneTwister{
-		state:  state,
+func FromSlice(state []uint32) (MersenneTwister, error) {
+	if len(state) != n {
+		return nil, InvalidStateSizeErr
+	}
+	mt := &mersenneTwister{
+		state:  make([]uint32, n),
 		index:  0,
 		seeded: true,
 	}
+	copy(mt.state, state)
+	return mt, nil
+}
+
+// Clone returns a generator that continues where the given consecutive outputs left off.
+// The outputs do not have to be aligned to a twist of the original generator, as the twist
+// recurrence holds for any window of 624 consecutive words.
+// Only the last 624 outputs are used to reconstruct the state.
+func Clone(outputs []int32) (MersenneTwister, error) {
+	if len(outputs) < n {
+		return nil, TooFewOutputsErr
+	}
+	outputs = outputs[len(outputs)-n:]
+
+	mt := &mersenneTwister{
+		state:  make([]uint32, n),
+		index:  n,
+		seeded: true,
+	}
+	for i, v := range outputs {
+		mt.state[i] = Untemper(v)
+	}
+	return mt, nil
 }
 
 func New() MersenneTwister {
@@ -45,3 +45,123 @@ func TestRand(t *testing.T) {
 		}
 	}
 }
+
+func outputs(mt MersenneTwister, count int) []int32 {
+	var res []int32
+	for i := 0; i < count; i++ {
+		v, _ := mt.Rand()
+		res = append(res, v)
+	}
+	return res
+}
+
+func TestUntemper(t *testing.T) {
+	for _, x := range []uint32{0, 1, 0x80000000, 0xffffffff, 0xdeadbeef, 5489} {
+		actual := Untemper(Temper(x))
+		if actual != x {
+			t.Fatalf("Expected %d, but got %d", x, actual)
+		}
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	if _, err := FromSlice(make([]uint32, n-1)); err != InvalidStateSizeErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidStateSizeErr, err)
+	}
+
+	mt := New()
+	mt.Seed(5489)
+	expected := outputs(mt, n)
+
+	state := make([]uint32, n)
+	for i, v := range expected {
+		state[i] = Untemper(v)
+	}
+	other, err := FromSlice(state)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	state[0] = 0
+
+	for i, actual := range outputs(other, n) {
+		if expected[i] != actual {
+			t.Fatalf("Expected %d, but got %d", expected[i], actual)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int
+	}{
+		{
+			name:   "Aligned to twist",
+			offset: 0,
+		},
+		{
+			name:   "Unaligned",
+			offset: 100,
+		},
+		{
+			name:   "Unaligned over multiple twists",
+			offset: 2*n + 311,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := New()
+			mt.Seed(1234)
+			outputs(mt, tt.offset)
+			observed := outputs(mt, n)
+
+			clone, err := Clone(observed)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			expected := outputs(mt, 2*n)
+			for i, actual := range outputs(clone, 2*n) {
+				if expected[i] != actual {
+					t.Fatalf("Expected future output %d to be %d, but got %d", i, expected[i], actual)
+				}
+			}
+		})
+	}
+
+	if _, err := Clone(make([]int32, n-1)); err != TooFewOutputsErr {
+		t.Fatalf("Expected error %s, but got %s", TooFewOutputsErr, err)
+	}
+}
+
+func TestPrev(t *testing.T) {
+	mt := New()
+	mt.Seed(42)
+	history := outputs(mt, 3*n+17)
+
+	clone, err := Clone(history[len(history)-n:])
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		actual, err := clone.Prev()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if history[i] != actual {
+			t.Fatalf("Expected past output %d to be %d, but got %d", i, history[i], actual)
+		}
+	}
+
+	// stepping forward again replays the history
+	for i, actual := range outputs(clone, len(history)) {
+		if history[i] != actual {
+			t.Fatalf("Expected output %d to be %d, but got %d", i, history[i], actual)
+		}
+	}
+
+	if _, err := New().Prev(); err != NotSeededErr {
+		t.Fatalf("Expected error %s, but got %s", NotSeededErr, err)
+	}
+}
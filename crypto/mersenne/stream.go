@@ -0,0 +1,92 @@
+package mersenne
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	SeedNotFoundErr = errors.New("failed to find seed")
+)
+
+// keystream returns the lowest byte of the first size outputs of a generator with the given seed
+func keystream(mt MersenneTwister, seed uint32, size int) []byte {
+	mt.Seed(int(seed))
+	ks := make([]byte, size)
+	for i := range ks {
+		v, _ := mt.Rand()
+		ks[i] = byte(v)
+	}
+	return ks
+}
+
+func xorKeystream(b []byte, ks []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[i] = b[i] ^ ks[i]
+	}
+	return res
+}
+
+type StreamCipher interface {
+	Encrypt(pt []byte) ([]byte, error)
+	Decrypt(ct []byte) ([]byte, error)
+}
+
+type streamCipher struct {
+	seed uint16
+}
+
+func (c *streamCipher) Encrypt(pt []byte) ([]byte, error) {
+	ks := keystream(New(), uint32(c.seed), len(pt))
+	return xorKeystream(pt, ks), nil
+}
+
+func (c *streamCipher) Decrypt(ct []byte) ([]byte, error) {
+	return c.Encrypt(ct)
+}
+
+// NewStreamCipher returns a stream cipher that uses the output of a generator seeded with a 16-bit key as keystream
+func NewStreamCipher(seed uint16) StreamCipher {
+	return &streamCipher{
+		seed: seed,
+	}
+}
+
+// RecoverStreamSeed brute-forces the 16-bit seed of a cipher text whose plain text ends with the known suffix
+func RecoverStreamSeed(ct []byte, suffix []byte) (uint16, error) {
+	if len(suffix) > len(ct) {
+		return 0, SeedNotFoundErr
+	}
+	offset := len(ct) - len(suffix)
+	target := ct[offset:]
+
+	mt := New()
+	for seed := 0; seed <= math.MaxUint16; seed++ {
+		ks := keystream(mt, uint32(seed), len(ct))
+		if bytes.Equal(xorKeystream(target, ks[offset:]), suffix) {
+			return uint16(seed), nil
+		}
+	}
+	return 0, SeedNotFoundErr
+}
+
+// TokenFromTime returns a password reset token generated by a generator seeded with the given time
+func TokenFromTime(t time.Time, size int) []byte {
+	return keystream(New(), uint32(t.Unix()), size)
+}
+
+// DetectTimeSeededToken determines whether the token was generated by a generator seeded with a
+// timestamp in the given range, and returns that timestamp if so
+func DetectTimeSeededToken(token []byte, r SeedRange) (uint32, bool) {
+	mt := New()
+	for i := uint64(0); i < r.size(); i++ {
+		seed := r.Lower + uint32(i)
+		if bytes.Equal(keystream(mt, seed, len(token)), token) {
+			return seed, true
+		}
+	}
+	return 0, false
+}
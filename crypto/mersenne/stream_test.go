@@ -0,0 +1,81 @@
+package mersenne
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestStreamCipher(t *testing.T) {
+	pt := []byte("some kind of somewhat long plain text!")
+	c := NewStreamCipher(1337)
+
+	ct, err := c.Encrypt(pt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if bytes.Equal(ct, pt) {
+		t.Fatalf("Expected cipher text to differ from plain text")
+	}
+	actual, err := c.Decrypt(ct)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(actual, pt) {
+		t.Fatalf("Expected plain text %q, but got %q", pt, actual)
+	}
+}
+
+func TestRecoverStreamSeed(t *testing.T) {
+	tests := []struct {
+		name string
+		seed uint16
+	}{
+		{
+			name: "Small seed",
+			seed: 3,
+		},
+		{
+			name: "Large seed",
+			seed: 0xfffe,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := make([]byte, 5+rand.Intn(10))
+			rand.Read(prefix)
+			known := bytes.Repeat([]byte("A"), 14)
+
+			ct, _ := NewStreamCipher(tt.seed).Encrypt(append(prefix, known...))
+
+			actual, err := RecoverStreamSeed(ct, known)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if actual != tt.seed {
+				t.Fatalf("Expected seed %d, but got %d", tt.seed, actual)
+			}
+		})
+	}
+}
+
+func TestDetectTimeSeededToken(t *testing.T) {
+	now := time.Now()
+	r := TimeRange(now.Add(-time.Hour), now)
+
+	token := TokenFromTime(now.Add(-10*time.Minute), 16)
+	seed, ok := DetectTimeSeededToken(token, r)
+	if !ok {
+		t.Fatalf("Expected token to be detected as time seeded")
+	}
+	if expected := uint32(now.Add(-10 * time.Minute).Unix()); seed != expected {
+		t.Fatalf("Expected seed %d, but got %d", expected, seed)
+	}
+
+	random := make([]byte, 16)
+	rand.Read(random)
+	if _, ok := DetectTimeSeededToken(random, r); ok {
+		t.Fatalf("Expected random token not to be detected as time seeded")
+	}
+}
@@ -34,6 +34,9 @@ func RemovePkcs7(b []byte, bsize int) ([]byte, error) {
 	if len(b)%bsize != 0 {
 		return nil, BlocksizeErr
 	}
+	if len(b) == 0 {
+		return nil, InvalidPaddingErr
+	}
 	padbyte := b[len(b)-1]
 	if padbyte < 1 || padbyte > aes.BlockSize {
 		return nil, InvalidPaddingErr
@@ -0,0 +1,128 @@
+package rijndael
+
+// coefficients of the MixColumns matrix by which a fault in row r of a column is multiplied
+var mixCoefficients = [4][4]byte{
+	{2, 1, 1, 3},
+	{3, 2, 1, 1},
+	{1, 3, 2, 1},
+	{1, 1, 3, 2},
+}
+
+type FaultyPair struct {
+	Correct []byte
+	Faulty  []byte
+}
+
+// lastRoundPositions returns the cipher text positions to which column col of the state after
+// the MixColumns of the penultimate round is moved by the final ShiftRows
+func lastRoundPositions(col int) [4]int {
+	var res [4]int
+	for r := 0; r < 4; r++ {
+		res[r] = r + 4*((col-r+4)%4)
+	}
+	return res
+}
+
+// faultedColumn returns the column that is affected by the fault, or -1 if the difference between
+// the cipher texts does not match a single byte fault at the start of the penultimate round
+func (p FaultyPair) faultedColumn() int {
+	for col := 0; col < 4; col++ {
+		positions := lastRoundPositions(col)
+		match := true
+		for i := 0; i < BlockSize; i++ {
+			inColumn := false
+			for _, pos := range positions {
+				if pos == i {
+					inColumn = true
+				}
+			}
+			if inColumn != (p.Correct[i] != p.Faulty[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return col
+		}
+	}
+	return -1
+}
+
+// columnCandidates returns the last round key bytes of a column that explain the faulty pair
+func (p FaultyPair) columnCandidates(col int) map[[4]byte]bool {
+	positions := lastRoundPositions(col)
+
+	// solutions[r][d] holds the key bytes for which the difference before the final SubBytes equals d
+	var solutions [4][256][]byte
+	for r, pos := range positions {
+		for k := 0; k < 256; k++ {
+			d := InvSbox[p.Correct[pos]^byte(k)] ^ InvSbox[p.Faulty[pos]^byte(k)]
+			solutions[r][d] = append(solutions[r][d], byte(k))
+		}
+	}
+
+	res := map[[4]byte]bool{}
+	for row := 0; row < 4; row++ {
+		for f := 1; f < 256; f++ {
+			var sets [4][]byte
+			empty := false
+			for r := 0; r < 4; r++ {
+				sets[r] = solutions[r][Mul(byte(f), mixCoefficients[row][r])]
+				if len(sets[r]) == 0 {
+					empty = true
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+			for _, k0 := range sets[0] {
+				for _, k1 := range sets[1] {
+					for _, k2 := range sets[2] {
+						for _, k3 := range sets[3] {
+							res[[4]byte{k0, k1, k2, k3}] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// PiretQuisquater recovers the last round key from pairs of correct and faulty cipher texts of the same
+// plain text, where the fault changed a single byte of the state at the start of the penultimate round.
+// Pairs that do not match this fault model are ignored. Two pairs per column usually suffice, three almost always do.
+func PiretQuisquater(pairs []FaultyPair) (State, error) {
+	var candidates [4]map[[4]byte]bool
+	for _, p := range pairs {
+		col := p.faultedColumn()
+		if col < 0 {
+			continue
+		}
+		found := p.columnCandidates(col)
+		if candidates[col] == nil {
+			candidates[col] = found
+			continue
+		}
+		for k := range candidates[col] {
+			if !found[k] {
+				delete(candidates[col], k)
+			}
+		}
+	}
+
+	var res State
+	for col, c := range candidates {
+		if len(c) != 1 {
+			return State{}, NoKeyFoundErr
+		}
+		positions := lastRoundPositions(col)
+		for k := range c {
+			for r, pos := range positions {
+				res[pos] = k[r]
+			}
+		}
+	}
+	return res, nil
+}
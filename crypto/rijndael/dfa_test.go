@@ -0,0 +1,64 @@
+package rijndael
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestPiretQuisquater(t *testing.T) {
+	// a fixed seed keeps the faults, and thereby the number of surviving key candidates, deterministic
+	r := rand.New(rand.NewSource(1))
+	key := make([]byte, 16)
+	r.Read(key)
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var pairs []FaultyPair
+	for col := 0; col < 4; col++ {
+		for i := 0; i < 3; i++ {
+			pt := make([]byte, BlockSize)
+			r.Read(pt)
+			correct := make([]byte, BlockSize)
+			faulty := make([]byte, BlockSize)
+			c.Encrypt(correct, pt)
+			// ShiftRows moves the faulty byte in the given row to column col
+			row := r.Intn(4)
+			f := Fault{
+				Round: 9,
+				Index: row + 4*((col+row)%4),
+				Mask:  byte(1 + r.Intn(255)),
+			}
+			if err := c.EncryptFault(faulty, pt, f); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			pairs = append(pairs, FaultyPair{correct, faulty})
+		}
+	}
+	// a fault in the wrong round must be ignored
+	pt := make([]byte, BlockSize)
+	correct := make([]byte, BlockSize)
+	faulty := make([]byte, BlockSize)
+	c.Encrypt(correct, pt)
+	if err := c.EncryptFault(faulty, pt, Fault{Round: 8, Index: 0, Mask: 1}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	pairs = append(pairs, FaultyPair{correct, faulty})
+
+	k, err := PiretQuisquater(pairs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := c.RoundKeys()[10]; k != expected {
+		t.Fatalf("Expected round key %x, but got %x", expected, k)
+	}
+	if actual := InvertKeySchedule(k, 10); !bytes.Equal(key, actual) {
+		t.Fatalf("Expected key %x, but got %x", key, actual)
+	}
+
+	if _, err := PiretQuisquater(pairs[:1]); err != NoKeyFoundErr {
+		t.Fatalf("Expected error %s, but got %s", NoKeyFoundErr, err)
+	}
+}
@@ -0,0 +1,324 @@
+// Package rijndael is a transparent implementation of AES, meant for inspecting the cipher's internals
+// and experimenting with attacks on it. It is not constant time and must not be used to protect data.
+package rijndael
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+const (
+	BlockSize = 16
+)
+
+var (
+	Sbox    [256]byte
+	InvSbox [256]byte
+	rcon    = []byte{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80, 0x1b, 0x36}
+)
+
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("invalid key size %d", int(k))
+}
+
+type RoundsError int
+
+func (r RoundsError) Error() string {
+	return fmt.Sprintf("invalid number of rounds %d", int(r))
+}
+
+type FaultIndexError int
+
+func (i FaultIndexError) Error() string {
+	return fmt.Sprintf("invalid fault index %d", int(i))
+}
+
+// State is the 4x4 byte matrix the cipher operates on, stored column by column as in FIPS-197
+type State [BlockSize]byte
+
+func init() {
+	// the S-box is the multiplicative inverse in GF(2^8) followed by an affine transformation
+	for i := 0; i < 256; i++ {
+		inv := byte(0)
+		if i != 0 {
+			inv = gInverse(byte(i))
+		}
+		s := inv ^ rotl(inv, 1) ^ rotl(inv, 2) ^ rotl(inv, 3) ^ rotl(inv, 4) ^ 0x63
+		Sbox[i] = s
+		InvSbox[s] = byte(i)
+	}
+}
+
+func rotl(b byte, n uint) byte {
+	return b<<n | b>>(8-n)
+}
+
+func xtime(b byte) byte {
+	if b&0x80 != 0 {
+		return b<<1 ^ 0x1b
+	}
+	return b << 1
+}
+
+// Mul multiplies two elements of GF(2^8) modulo the AES polynomial
+func Mul(a, b byte) byte {
+	var res byte
+	for b > 0 {
+		if b&1 != 0 {
+			res ^= a
+		}
+		a = xtime(a)
+		b >>= 1
+	}
+	return res
+}
+
+func gInverse(b byte) byte {
+	// b^254 is the inverse of b, as the multiplicative group has order 255
+	res := byte(1)
+	for i := 0; i < 254; i++ {
+		res = Mul(res, b)
+	}
+	return res
+}
+
+func (s *State) SubBytes() {
+	for i := range s {
+		s[i] = Sbox[s[i]]
+	}
+}
+
+func (s *State) InvSubBytes() {
+	for i := range s {
+		s[i] = InvSbox[s[i]]
+	}
+}
+
+func (s *State) ShiftRows() {
+	var res State
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			res[r+4*c] = s[r+4*((c+r)%4)]
+		}
+	}
+	*s = res
+}
+
+func (s *State) InvShiftRows() {
+	var res State
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			res[r+4*((c+r)%4)] = s[r+4*c]
+		}
+	}
+	*s = res
+}
+
+func (s *State) MixColumns() {
+	for c := 0; c < 4; c++ {
+		a0, a1, a2, a3 := s[4*c], s[4*c+1], s[4*c+2], s[4*c+3]
+		s[4*c] = Mul(a0, 2) ^ Mul(a1, 3) ^ a2 ^ a3
+		s[4*c+1] = a0 ^ Mul(a1, 2) ^ Mul(a2, 3) ^ a3
+		s[4*c+2] = a0 ^ a1 ^ Mul(a2, 2) ^ Mul(a3, 3)
+		s[4*c+3] = Mul(a0, 3) ^ a1 ^ a2 ^ Mul(a3, 2)
+	}
+}
+
+func (s *State) InvMixColumns() {
+	for c := 0; c < 4; c++ {
+		a0, a1, a2, a3 := s[4*c], s[4*c+1], s[4*c+2], s[4*c+3]
+		s[4*c] = Mul(a0, 14) ^ Mul(a1, 11) ^ Mul(a2, 13) ^ Mul(a3, 9)
+		s[4*c+1] = Mul(a0, 9) ^ Mul(a1, 14) ^ Mul(a2, 11) ^ Mul(a3, 13)
+		s[4*c+2] = Mul(a0, 13) ^ Mul(a1, 9) ^ Mul(a2, 14) ^ Mul(a3, 11)
+		s[4*c+3] = Mul(a0, 11) ^ Mul(a1, 13) ^ Mul(a2, 9) ^ Mul(a3, 14)
+	}
+}
+
+func (s *State) AddRoundKey(k State) {
+	for i := range s {
+		s[i] ^= k[i]
+	}
+}
+
+// Fault describes a single-byte fault, which XORs Mask into byte Index of the state at the start of round Round.
+// A fault in round 0 is applied to the plain text before the initial round key is added.
+type Fault struct {
+	Round int
+	Index int
+	Mask  byte
+}
+
+type Cipher struct {
+	rounds    int
+	roundKeys []State
+}
+
+// NewCipher returns an AES cipher with the standard number of rounds for the key size of 16, 24 or 32 bytes
+func NewCipher(key []byte) (*Cipher, error) {
+	switch len(key) {
+	case 16, 24, 32:
+		return NewReducedCipher(key, len(key)/4+6)
+	}
+	return nil, KeySizeError(len(key))
+}
+
+// NewReducedCipher returns an AES cipher with the given number of rounds.
+// As in the full cipher, the last round omits MixColumns.
+func NewReducedCipher(key []byte, rounds int) (*Cipher, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+	if rounds < 1 || rounds > len(key)/4+6 {
+		return nil, RoundsError(rounds)
+	}
+	return &Cipher{
+		rounds:    rounds,
+		roundKeys: ExpandKey(key, rounds),
+	}, nil
+}
+
+// ExpandKey returns the round keys for the given number of rounds, including the initial round key
+func ExpandKey(key []byte, rounds int) []State {
+	nk := len(key) / 4
+	words := make([][4]byte, 4*(rounds+1))
+	for i := 0; i < nk && i < len(words); i++ {
+		copy(words[i][:], key[4*i:4*i+4])
+	}
+	for i := nk; i < len(words); i++ {
+		tmp := words[i-1]
+		if i%nk == 0 {
+			tmp = [4]byte{Sbox[tmp[1]], Sbox[tmp[2]], Sbox[tmp[3]], Sbox[tmp[0]]}
+			tmp[0] ^= rcon[i/nk-1]
+		} else if nk > 6 && i%nk == 4 {
+			tmp = [4]byte{Sbox[tmp[0]], Sbox[tmp[1]], Sbox[tmp[2]], Sbox[tmp[3]]}
+		}
+		for j := range tmp {
+			words[i][j] = words[i-nk][j] ^ tmp[j]
+		}
+	}
+
+	keys := make([]State, rounds+1)
+	for r := range keys {
+		for c := 0; c < 4; c++ {
+			copy(keys[r][4*c:4*c+4], words[4*r+c][:])
+		}
+	}
+	return keys
+}
+
+// InvertKeySchedule returns the AES-128 key from which the given round key was derived
+func InvertKeySchedule(k State, round int) []byte {
+	words := make([][4]byte, 4*(round+1))
+	for c := 0; c < 4; c++ {
+		copy(words[4*round+c][:], k[4*c:4*c+4])
+	}
+	for i := 4*round + 3; i >= 4; i-- {
+		tmp := words[i-1]
+		if i%4 == 0 {
+			tmp = [4]byte{Sbox[tmp[1]], Sbox[tmp[2]], Sbox[tmp[3]], Sbox[tmp[0]]}
+			tmp[0] ^= rcon[i/4-1]
+		}
+		for j := range tmp {
+			words[i-4][j] = words[i][j] ^ tmp[j]
+		}
+	}
+
+	key := make([]byte, 16)
+	for c := 0; c < 4; c++ {
+		copy(key[4*c:4*c+4], words[c][:])
+	}
+	return key
+}
+
+func (c *Cipher) BlockSize() int {
+	return BlockSize
+}
+
+func (c *Cipher) Rounds() int {
+	return c.rounds
+}
+
+// RoundKeys returns a copy of the key schedule, starting with the initial round key
+func (c *Cipher) RoundKeys() []State {
+	res := make([]State, len(c.roundKeys))
+	copy(res, c.roundKeys)
+	return res
+}
+
+// Trace encrypts a single block and returns the state after the initial round key and after each round,
+// so the last state is the cipher text
+func (c *Cipher) Trace(src []byte) []State {
+	return c.encrypt(src, nil)
+}
+
+func (c *Cipher) Encrypt(dst, src []byte) {
+	states := c.encrypt(src, nil)
+	last := states[len(states)-1]
+	copy(dst, last[:])
+}
+
+// EncryptFault encrypts a single block while injecting the given fault
+func (c *Cipher) EncryptFault(dst, src []byte, f Fault) error {
+	if f.Index < 0 || f.Index >= BlockSize {
+		return FaultIndexError(f.Index)
+	}
+	states := c.encrypt(src, &f)
+	last := states[len(states)-1]
+	copy(dst, last[:])
+	return nil
+}
+
+func (c *Cipher) encrypt(src []byte, f *Fault) []State {
+	if len(src) < BlockSize {
+		panic("rijndael: input not full block")
+	}
+	var s State
+	copy(s[:], src)
+
+	inject := func(round int) {
+		if f != nil && f.Round == round {
+			s[f.Index] ^= f.Mask
+		}
+	}
+
+	states := make([]State, 0, c.rounds+1)
+	inject(0)
+	s.AddRoundKey(c.roundKeys[0])
+	states = append(states, s)
+	for r := 1; r <= c.rounds; r++ {
+		inject(r)
+		s.SubBytes()
+		s.ShiftRows()
+		if r != c.rounds {
+			s.MixColumns()
+		}
+		s.AddRoundKey(c.roundKeys[r])
+		states = append(states, s)
+	}
+	return states
+}
+
+func (c *Cipher) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("rijndael: input not full block")
+	}
+	var s State
+	copy(s[:], src)
+
+	for r := c.rounds; r >= 1; r-- {
+		s.AddRoundKey(c.roundKeys[r])
+		if r != c.rounds {
+			s.InvMixColumns()
+		}
+		s.InvShiftRows()
+		s.InvSubBytes()
+	}
+	s.AddRoundKey(c.roundKeys[0])
+	copy(dst, s[:])
+}
+
+var _ cipher.Block = (*Cipher)(nil)
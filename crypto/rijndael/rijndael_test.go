@@ -0,0 +1,209 @@
+package rijndael
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSbox(t *testing.T) {
+	tests := []struct {
+		in       byte
+		expected byte
+	}{
+		{0x00, 0x63},
+		{0x01, 0x7c},
+		{0x53, 0xed},
+		{0xff, 0x16},
+	}
+	for _, tt := range tests {
+		if Sbox[tt.in] != tt.expected {
+			t.Fatalf("Expected S-box value %02x for %02x, but got %02x", tt.expected, tt.in, Sbox[tt.in])
+		}
+		if InvSbox[tt.expected] != tt.in {
+			t.Fatalf("Expected inverse S-box value %02x for %02x, but got %02x", tt.in, tt.expected, InvSbox[tt.expected])
+		}
+	}
+}
+
+// Test vectors from FIPS-197, appendix C
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      []byte
+		expected []byte
+	}{
+		{
+			name:     "AES-128",
+			key:      unhex("000102030405060708090a0b0c0d0e0f"),
+			expected: unhex("69c4e0d86a7b0430d8cdb78070b4c55a"),
+		},
+		{
+			name:     "AES-192",
+			key:      unhex("000102030405060708090a0b0c0d0e0f1011121314151617"),
+			expected: unhex("dda97ca4864cdfe06eaf70a0ec0d7191"),
+		},
+		{
+			name:     "AES-256",
+			key:      unhex("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"),
+			expected: unhex("8ea2b7ca516745bfeafc49904b496089"),
+		},
+	}
+	pt := unhex("00112233445566778899aabbccddeeff")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCipher(tt.key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			ct := make([]byte, BlockSize)
+			c.Encrypt(ct, pt)
+			if !bytes.Equal(ct, tt.expected) {
+				t.Fatalf("Expected cipher text %x, but got %x", tt.expected, ct)
+			}
+
+			actual := make([]byte, BlockSize)
+			c.Decrypt(actual, ct)
+			if !bytes.Equal(actual, pt) {
+				t.Fatalf("Expected plain text %x, but got %x", pt, actual)
+			}
+		})
+	}
+}
+
+// Round states and key schedule from FIPS-197, appendices A.1 and B
+func TestTrace(t *testing.T) {
+	c, err := NewCipher(unhex("2b7e151628aed2a6abf7158809cf4f3c"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	keys := c.RoundKeys()
+	if len(keys) != 11 {
+		t.Fatalf("Expected %d round keys, but got %d", 11, len(keys))
+	}
+	if expected := unhex("d014f9a8c9ee2589e13f0cc8b6630ca6"); !bytes.Equal(keys[10][:], expected) {
+		t.Fatalf("Expected last round key %x, but got %x", expected, keys[10])
+	}
+
+	states := c.Trace(unhex("3243f6a8885a308d313198a2e0370734"))
+	expected := map[int][]byte{
+		0:  unhex("193de3bea0f4e22b9ac68d2ae9f84808"),
+		1:  unhex("a49c7ff2689f352b6b5bea43026a5049"),
+		9:  unhex("eb40f21e592e38848ba113e71bc342d2"),
+		10: unhex("3925841d02dc09fbdc118597196a0b32"),
+	}
+	for r, e := range expected {
+		if !bytes.Equal(states[r][:], e) {
+			t.Fatalf("Expected state %x after round %d, but got %x", e, r, states[r])
+		}
+	}
+}
+
+func TestDifferential(t *testing.T) {
+	for _, ksize := range []int{16, 24, 32} {
+		for i := 0; i < 100; i++ {
+			key := make([]byte, ksize)
+			pt := make([]byte, BlockSize)
+			rand.Read(key)
+			rand.Read(pt)
+
+			expected := make([]byte, BlockSize)
+			std, _ := aes.NewCipher(key)
+			std.Encrypt(expected, pt)
+
+			c, err := NewCipher(key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			actual := make([]byte, BlockSize)
+			c.Encrypt(actual, pt)
+			if !bytes.Equal(expected, actual) {
+				t.Fatalf("Expected cipher text %x for key %x, but got %x", expected, key, actual)
+			}
+
+			c.Decrypt(actual, expected)
+			if !bytes.Equal(pt, actual) {
+				t.Fatalf("Expected plain text %x for key %x, but got %x", pt, key, actual)
+			}
+		}
+	}
+}
+
+func TestReducedCipher(t *testing.T) {
+	key := unhex("000102030405060708090a0b0c0d0e0f")
+	pt := unhex("00112233445566778899aabbccddeeff")
+	for rounds := 1; rounds <= 10; rounds++ {
+		c, err := NewReducedCipher(key, rounds)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		ct := make([]byte, BlockSize)
+		c.Encrypt(ct, pt)
+		actual := make([]byte, BlockSize)
+		c.Decrypt(actual, ct)
+		if !bytes.Equal(pt, actual) {
+			t.Fatalf("Expected plain text %x for %d rounds, but got %x", pt, rounds, actual)
+		}
+	}
+
+	if _, err := NewReducedCipher(key, 11); err != RoundsError(11) {
+		t.Fatalf("Expected error %s, but got %s", RoundsError(11), err)
+	}
+	if _, err := NewCipher(key[:15]); err != KeySizeError(15) {
+		t.Fatalf("Expected error %s, but got %s", KeySizeError(15), err)
+	}
+}
+
+func TestEncryptFault(t *testing.T) {
+	c, _ := NewCipher(unhex("000102030405060708090a0b0c0d0e0f"))
+	pt := unhex("00112233445566778899aabbccddeeff")
+
+	correct := make([]byte, BlockSize)
+	c.Encrypt(correct, pt)
+
+	// a fault at the start of the last round only affects a single byte after ShiftRows
+	faulty := make([]byte, BlockSize)
+	if err := c.EncryptFault(faulty, pt, Fault{Round: 10, Index: 1, Mask: 0x01}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	diff := 0
+	for i := range correct {
+		if correct[i] != faulty[i] {
+			diff++
+		}
+	}
+	if diff != 1 || correct[13] == faulty[13] {
+		t.Fatalf("Expected only byte 13 to differ, but got %x and %x", correct, faulty)
+	}
+
+	// a fault at the start of round 9 spreads to a full column
+	if err := c.EncryptFault(faulty, pt, Fault{Round: 9, Index: 0, Mask: 0xff}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	diff = 0
+	for i := range correct {
+		if correct[i] != faulty[i] {
+			diff++
+		}
+	}
+	if diff != 4 {
+		t.Fatalf("Expected four bytes to differ, but got %d", diff)
+	}
+
+	for _, index := range []int{-1, BlockSize} {
+		if err := c.EncryptFault(faulty, pt, Fault{Round: 9, Index: index, Mask: 1}); err != FaultIndexError(index) {
+			t.Fatalf("Expected error %s, but got %v", FaultIndexError(index), err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package rijndael
+
+import (
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"math/rand"
+)
+
+const (
+	maxLambdaSets = 32
+)
+
+var (
+	NoKeyFoundErr = errors.New("failed to find round key")
+)
+
+// SquareAttack recovers the last round key of 4-round AES using the integral (Square) attack.
+// After three rounds every byte of a Λ-set, a set of 256 plain texts that differ in a single byte only,
+// XORs to zero, so each byte of the last round key can be guessed independently.
+// It returns the last round key and the number of queries made to the encryption oracle.
+func SquareAttack(oracle crypto.Oracle) (State, int, error) {
+	var candidates [BlockSize][]byte
+	for i := range candidates {
+		for g := 0; g < 256; g++ {
+			candidates[i] = append(candidates[i], byte(g))
+		}
+	}
+
+	queries := 0
+	for set := 0; set < maxLambdaSets; set++ {
+		base := make([]byte, BlockSize)
+		rand.Read(base)
+
+		var cts [][]byte
+		for a := 0; a < 256; a++ {
+			pt := make([]byte, BlockSize)
+			copy(pt, base)
+			pt[0] = byte(a)
+			ct, err := oracle(pt)
+			if err != nil {
+				return State{}, queries, err
+			}
+			queries++
+			cts = append(cts, ct)
+		}
+
+		done := true
+		for i := range candidates {
+			var remaining []byte
+			for _, g := range candidates[i] {
+				var sum byte
+				for _, ct := range cts {
+					sum ^= InvSbox[ct[i]^g]
+				}
+				if sum == 0 {
+					remaining = append(remaining, g)
+				}
+			}
+			if len(remaining) == 0 {
+				return State{}, queries, NoKeyFoundErr
+			}
+			candidates[i] = remaining
+			if len(remaining) > 1 {
+				done = false
+			}
+		}
+
+		if done {
+			var k State
+			for i := range candidates {
+				k[i] = candidates[i][0]
+			}
+			return k, queries, nil
+		}
+	}
+	return State{}, queries, NoKeyFoundErr
+}
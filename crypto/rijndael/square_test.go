@@ -0,0 +1,48 @@
+package rijndael
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestInvertKeySchedule(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	keys := ExpandKey(key, 10)
+
+	for r := range keys {
+		actual := InvertKeySchedule(keys[r], r)
+		if !bytes.Equal(key, actual) {
+			t.Fatalf("Expected key %x from round %d, but got %x", key, r, actual)
+		}
+	}
+}
+
+func TestSquareAttack(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	c, err := NewReducedCipher(key, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	oracle := func(pt []byte) ([]byte, error) {
+		ct := make([]byte, BlockSize)
+		c.Encrypt(ct, pt)
+		return ct, nil
+	}
+
+	k, queries, err := SquareAttack(oracle)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if queries%256 != 0 || queries == 0 {
+		t.Fatalf("Expected queries to be a multiple of 256, but got %d", queries)
+	}
+	if expected := c.RoundKeys()[4]; k != expected {
+		t.Fatalf("Expected round key %x, but got %x", expected, k)
+	}
+	if actual := InvertKeySchedule(k, 4); !bytes.Equal(key, actual) {
+		t.Fatalf("Expected key %x, but got %x", key, actual)
+	}
+}
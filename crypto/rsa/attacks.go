@@ -0,0 +1,157 @@
+package rsa
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"io"
+	"math/big"
+)
+
+var (
+	TooFewCapturesErr   = errors.New("need at least e captures for the broadcast attack")
+	ExponentMismatchErr = errors.New("captures use different public exponents")
+	NoRootErr           = errors.New("combined ciphertext has no exact root")
+	AlreadyDecryptedErr = errors.New("ciphertext has already been decrypted")
+)
+
+// ParityOracle reports whether the decryption of the ciphertext is even
+type ParityOracle func(ct []byte) (bool, error)
+
+// Capture is a ciphertext intercepted together with the public key it was encrypted under
+type Capture struct {
+	Ct  []byte
+	Key *PublicKey
+}
+
+// Broadcast recovers a message that was encrypted without padding under e different public keys with exponent e.
+// The ciphertexts are combined with the Chinese remainder theorem into m^e modulo the product of the moduli,
+// which is smaller than that product, so that the message is simply its integer e-th root.
+func Broadcast(captures []Capture) ([]byte, error) {
+	if len(captures) == 0 {
+		return nil, TooFewCapturesErr
+	}
+	e := captures[0].Key.E
+	if !e.IsInt64() || int64(len(captures)) < e.Int64() {
+		return nil, TooFewCapturesErr
+	}
+	captures = captures[:e.Int64()]
+
+	var residues, moduli []*big.Int
+	for _, c := range captures {
+		if c.Key.E.Cmp(e) != 0 {
+			return nil, ExponentMismatchErr
+		}
+		residues = append(residues, BytesToInt(c.Ct))
+		moduli = append(moduli, c.Key.N)
+	}
+	x, err := Crt(residues, moduli)
+	if err != nil {
+		return nil, err
+	}
+	m, exact := Root(x, int(e.Int64()))
+	if !exact {
+		return nil, NoRootErr
+	}
+	return m.Bytes(), nil
+}
+
+// UnpaddedOracle returns an oracle that decrypts each ciphertext at most once
+func UnpaddedOracle(k *PrivateKey) crypto.Oracle {
+	seen := map[string]bool{}
+	return func(ct []byte) ([]byte, error) {
+		c := BytesToInt(ct)
+		if seen[c.String()] {
+			return nil, AlreadyDecryptedErr
+		}
+		seen[c.String()] = true
+		m, err := k.DecryptCrt(c)
+		if err != nil {
+			return nil, err
+		}
+		return m.Bytes(), nil
+	}
+}
+
+// RecoverUnpadded recovers the plaintext of a ciphertext the oracle refuses to decrypt again,
+// by decrypting the blinded ciphertext s^e * c instead and dividing the result by s
+func RecoverUnpadded(pub *PublicKey, ct []byte, decrypt crypto.Oracle) ([]byte, error) {
+	s := big.NewInt(2)
+	sInv, err := InvMod(s, pub.N)
+	if err != nil {
+		return nil, err
+	}
+	c := new(big.Int).Exp(s, pub.E, pub.N)
+	c.Mul(c, BytesToInt(ct))
+	c.Mod(c, pub.N)
+
+	pt, err := decrypt(IntToBytes(c, pub.Size()))
+	if err != nil {
+		return nil, err
+	}
+	p := BytesToInt(pt)
+	p.Mul(p, sInv)
+	p.Mod(p, pub.N)
+	return p.Bytes(), nil
+}
+
+// NewParityOracle returns an oracle that leaks the least significant bit of the plaintext
+func NewParityOracle(k *PrivateKey) ParityOracle {
+	return func(ct []byte) (bool, error) {
+		m, err := k.DecryptCrt(BytesToInt(ct))
+		if err != nil {
+			return false, err
+		}
+		return m.Bit(0) == 0, nil
+	}
+}
+
+// ParityAttack decrypts the ciphertext with a parity oracle.
+// Multiplying the ciphertext by 2^e doubles the plaintext, which is even as long as it does not wrap around the
+// modulus. Each query therefore halves the interval in which the plaintext lies; progress is called with the upper
+// bound after every query.
+func ParityAttack(pub *PublicKey, ct []byte, oracle ParityOracle, progress func(upper []byte)) ([]byte, error) {
+	double := new(big.Int).Exp(two, pub.E, pub.N)
+	c := BytesToInt(ct)
+
+	// the plaintext lies in [lo * n / 2^i, hi * n / 2^i]
+	lo, hi := big.NewInt(0), big.NewInt(1)
+	bound := new(big.Int)
+	for i := 1; i <= pub.N.BitLen(); i++ {
+		c.Mul(c, double)
+		c.Mod(c, pub.N)
+		even, err := oracle(IntToBytes(c, pub.Size()))
+		if err != nil {
+			return nil, err
+		}
+		lo.Lsh(lo, 1)
+		hi.Lsh(hi, 1)
+		if even {
+			hi.Sub(hi, one)
+		} else {
+			lo.Add(lo, one)
+		}
+		if progress != nil {
+			bound.Mul(hi, pub.N)
+			bound.Rsh(bound, uint(i))
+			progress(bound.Bytes())
+		}
+	}
+	bound.Mul(hi, pub.N)
+	bound.Rsh(bound, uint(pub.N.BitLen()))
+	return bound.Bytes(), nil
+}
+
+// Hollywood returns a progress function that keeps rewriting a single line with the printable bytes of the bound
+func Hollywood(w io.Writer) func(upper []byte) {
+	return func(upper []byte) {
+		line := make([]byte, len(upper))
+		for i, b := range upper {
+			if b < 0x20 || b > 0x7e {
+				b = '.'
+			}
+			line[i] = b
+		}
+		fmt.Fprintf(w, "\r%s", line)
+	}
+}
@@ -0,0 +1,97 @@
+package rsa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	msg := []byte("broadcasting to three recipients")
+
+	var captures []Capture
+	for i := 0; i < 3; i++ {
+		k, err := GenerateKey(512, 3)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		ct, err := k.EncryptBytes(msg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		captures = append(captures, Capture{ct, &k.PublicKey})
+	}
+
+	actual, err := Broadcast(captures)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(msg, actual) {
+		t.Fatalf("Expected %q, but got %q", msg, actual)
+	}
+
+	if _, err := Broadcast(captures[:2]); err != TooFewCapturesErr {
+		t.Fatalf("Expected error %s, but got %v", TooFewCapturesErr, err)
+	}
+}
+
+func TestRecoverUnpadded(t *testing.T) {
+	k, err := GenerateKey(1024, 65537)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	msg := []byte(`{"time": 1356304276, "social": "555-55-5555"}`)
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	decrypt := UnpaddedOracle(k)
+	if _, err := decrypt(ct); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := decrypt(ct); err != AlreadyDecryptedErr {
+		t.Fatalf("Expected error %s, but got %v", AlreadyDecryptedErr, err)
+	}
+
+	actual, err := RecoverUnpadded(&k.PublicKey, ct, decrypt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(msg, actual) {
+		t.Fatalf("Expected %q, but got %q", msg, actual)
+	}
+}
+
+func TestParityAttack(t *testing.T) {
+	k, err := GenerateKey(1024, 65537)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	msg := []byte("That's why I found you don't play around with the Funky Cold Medina")
+	ct, err := k.EncryptBytes(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	calls := 0
+	actual, err := ParityAttack(&k.PublicKey, ct, NewParityOracle(k), func(upper []byte) {
+		calls++
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(msg, actual) {
+		t.Fatalf("Expected %q, but got %q", msg, actual)
+	}
+	if calls != k.N.BitLen() {
+		t.Fatalf("Expected %d progress calls, but got %d", k.N.BitLen(), calls)
+	}
+}
+
+func TestHollywood(t *testing.T) {
+	var buf bytes.Buffer
+	Hollywood(&buf)([]byte("ab\x00\xffc"))
+	if expected := "\rab..c"; buf.String() != expected {
+		t.Fatalf("Expected %q, but got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,203 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+)
+
+// Result is the outcome of a padding oracle attack
+type Result struct {
+	Plaintext []byte
+	Queries   int
+}
+
+type interval struct {
+	a, b *big.Int
+}
+
+func ceilDiv(x, y *big.Int) *big.Int {
+	q, m := new(big.Int).DivMod(x, y, new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, one)
+	}
+	return q
+}
+
+func floorDiv(x, y *big.Int) *big.Int {
+	return new(big.Int).Div(x, y)
+}
+
+// union inserts the interval into the sorted, disjoint intervals, merging overlapping ones
+func union(m []interval, n interval) []interval {
+	m = append(m, n)
+	sort.Slice(m, func(i, j int) bool {
+		return m[i].a.Cmp(m[j].a) < 0
+	})
+	res := []interval{m[0]}
+	for _, in := range m[1:] {
+		last := &res[len(res)-1]
+		if in.a.Cmp(last.b) <= 0 {
+			if in.b.Cmp(last.b) > 0 {
+				last.b = in.b
+			}
+			continue
+		}
+		res = append(res, in)
+	}
+	return res
+}
+
+type bleichenbacher struct {
+	pub     *PublicKey
+	c0      *big.Int
+	oracle  PaddingOracle
+	queries int
+}
+
+// conforms queries the oracle with c0 * s^e
+func (bb *bleichenbacher) conforms(s *big.Int) (bool, error) {
+	c := new(big.Int).Exp(s, bb.pub.E, bb.pub.N)
+	c.Mul(c, bb.c0)
+	c.Mod(c, bb.pub.N)
+	bb.queries++
+	return bb.oracle(IntToBytes(c, bb.pub.Size()))
+}
+
+// search returns the first s >= from for which c0 * s^e is conforming
+func (bb *bleichenbacher) search(from *big.Int) (*big.Int, error) {
+	s := new(big.Int).Set(from)
+	for {
+		ok, err := bb.conforms(s)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return s, nil
+		}
+		s.Add(s, one)
+	}
+}
+
+// Bleichenbacher decrypts the ciphertext with an oracle that reports whether a ciphertext decrypts to a plaintext
+// starting with 00 02, following Bleichenbacher's "Chosen Ciphertext Attacks Against Protocols Based on the RSA
+// Encryption Standard PKCS #1" (1998). The returned plaintext includes the padding.
+func Bleichenbacher(pub *PublicKey, ct []byte, oracle PaddingOracle) (Result, error) {
+	n := pub.N
+	k := pub.Size()
+	B := new(big.Int).Lsh(one, uint(8*(k-2)))
+	B2 := new(big.Int).Mul(B, two)
+	B3 := new(big.Int).Mul(B, three)
+	B3m1 := new(big.Int).Sub(B3, one)
+
+	bb := &bleichenbacher{
+		pub:    pub,
+		oracle: oracle,
+	}
+
+	// step 1: blinding, which is skipped when the ciphertext is already conforming
+	c := BytesToInt(ct)
+	s0 := big.NewInt(1)
+	bb.c0 = c
+	for {
+		ok, err := bb.conforms(s0)
+		if err != nil {
+			return Result{}, err
+		}
+		if ok {
+			break
+		}
+		s, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return Result{}, err
+		}
+		s0 = s
+	}
+	bb.c0 = new(big.Int).Exp(s0, pub.E, n)
+	bb.c0.Mul(bb.c0, c)
+	bb.c0.Mod(bb.c0, n)
+
+	M := []interval{{new(big.Int).Set(B2), new(big.Int).Set(B3m1)}}
+	var s *big.Int
+	for i := 1; ; i++ {
+		var err error
+		switch {
+		case i == 1:
+			// step 2a: find the smallest s >= n / 3B such that c0 * s^e is conforming
+			s, err = bb.search(ceilDiv(n, B3))
+		case len(M) > 1:
+			// step 2b: search with more than one interval left
+			s, err = bb.search(new(big.Int).Add(s, one))
+		default:
+			// step 2c: search with one interval left, which roughly halves the interval each iteration
+			s, err = bb.searchSingle(M[0], s, B2, B3)
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		// step 3: narrow the set of solutions
+		var next []interval
+		for _, in := range M {
+			rLo := ceilDiv(new(big.Int).Sub(new(big.Int).Mul(in.a, s), B3m1), n)
+			rHi := floorDiv(new(big.Int).Sub(new(big.Int).Mul(in.b, s), B2), n)
+			for r := rLo; r.Cmp(rHi) <= 0; r = new(big.Int).Add(r, one) {
+				rn := new(big.Int).Mul(r, n)
+				a := ceilDiv(new(big.Int).Add(B2, rn), s)
+				if a.Cmp(in.a) < 0 {
+					a = in.a
+				}
+				b := floorDiv(new(big.Int).Add(B3m1, rn), s)
+				if b.Cmp(in.b) > 0 {
+					b = in.b
+				}
+				if a.Cmp(b) > 0 {
+					continue
+				}
+				next = union(next, interval{a, b})
+			}
+		}
+		if len(next) == 0 {
+			return Result{}, InvalidPaddingErr
+		}
+		M = next
+
+		// step 4: compute the solution once a single value remains
+		if len(M) == 1 && M[0].a.Cmp(M[0].b) == 0 {
+			inv, err := InvMod(s0, n)
+			if err != nil {
+				return Result{}, err
+			}
+			m := inv.Mul(inv, M[0].a)
+			m.Mod(m, n)
+			return Result{
+				Plaintext: IntToBytes(m, k),
+				Queries:   bb.queries,
+			}, nil
+		}
+	}
+}
+
+func (bb *bleichenbacher) searchSingle(in interval, prev, B2, B3 *big.Int) (*big.Int, error) {
+	n := bb.pub.N
+	// r >= 2 * (b * s - 2B) / n
+	r := new(big.Int).Mul(in.b, prev)
+	r.Sub(r, B2)
+	r.Mul(r, two)
+	r = ceilDiv(r, n)
+	for ; ; r.Add(r, one) {
+		rn := new(big.Int).Mul(r, n)
+		// (2B + r * n) / b <= s < (3B + r * n) / a
+		lo := ceilDiv(new(big.Int).Add(B2, rn), in.b)
+		hi := ceilDiv(new(big.Int).Add(B3, rn), in.a)
+		for s := lo; s.Cmp(hi) < 0; s.Add(s, one) {
+			ok, err := bb.conforms(s)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return s, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rsa
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	NotCoprimeErr     = errors.New("moduli are not pairwise coprime")
+	LengthMismatchErr = errors.New("number of residues and moduli differ")
+)
+
+// Crt returns the unique x modulo the product of the moduli such that x = residues[i] mod moduli[i] for all i
+func Crt(residues, moduli []*big.Int) (*big.Int, error) {
+	if len(residues) != len(moduli) {
+		return nil, LengthMismatchErr
+	}
+	product := big.NewInt(1)
+	for _, m := range moduli {
+		product.Mul(product, m)
+	}
+	res := big.NewInt(0)
+	for i, m := range moduli {
+		ms := new(big.Int).Div(product, m)
+		inv, err := InvMod(ms, m)
+		if err != nil {
+			return nil, NotCoprimeErr
+		}
+		term := new(big.Int).Mul(residues[i], ms)
+		term.Mul(term, inv)
+		res.Add(res, term)
+	}
+	return res.Mod(res, product), nil
+}
+
+// Root returns the integer k-th root of x, rounded down, and whether the root is exact
+func Root(x *big.Int, k int) (*big.Int, bool) {
+	if x.Sign() <= 0 {
+		return big.NewInt(0), x.Sign() == 0
+	}
+	K := big.NewInt(int64(k))
+	km1 := big.NewInt(int64(k - 1))
+
+	// start from a power of two that is guaranteed to be larger than the root
+	r := new(big.Int).Lsh(one, uint(x.BitLen()/k+1))
+	pow, next := new(big.Int), new(big.Int)
+	for {
+		// Newton's method: r' = ((k-1) * r + x / r^(k-1)) / k
+		pow.Exp(r, km1, nil)
+		next.Div(x, pow)
+		next.Add(next, new(big.Int).Mul(km1, r))
+		next.Div(next, K)
+		if next.Cmp(r) >= 0 {
+			break
+		}
+		r.Set(next)
+	}
+	return r, pow.Exp(r, K, nil).Cmp(x) == 0
+}
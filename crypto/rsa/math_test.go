@@ -0,0 +1,51 @@
+package rsa
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+)
+
+func TestCrt(t *testing.T) {
+	moduli := []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)}
+	residues := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(2)}
+	actual, err := Crt(residues, moduli)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual.Int64() != 23 {
+		t.Fatalf("Expected %d, but got %s", 23, actual)
+	}
+
+	if _, err := Crt(residues, []*big.Int{big.NewInt(3), big.NewInt(6), big.NewInt(7)}); err != NotCoprimeErr {
+		t.Fatalf("Expected error %s, but got %v", NotCoprimeErr, err)
+	}
+	if _, err := Crt(residues[:2], moduli); err != LengthMismatchErr {
+		t.Fatalf("Expected error %s, but got %v", LengthMismatchErr, err)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	r := mrand.New(mrand.NewSource(1))
+	max := new(big.Int).Lsh(one, 700)
+	for _, k := range []int{2, 3, 5} {
+		for i := 0; i < 50; i++ {
+			root := new(big.Int).Rand(r, max)
+			root.Add(root, two)
+			x := new(big.Int).Exp(root, big.NewInt(int64(k)), nil)
+			actual, exact := Root(x, k)
+			if !exact || actual.Cmp(root) != 0 {
+				t.Fatalf("Expected exact root %s, but got %s (%t)", root, actual, exact)
+			}
+
+			// x + 1 has the same root rounded down, which is no longer exact
+			actual, exact = Root(x.Add(x, one), k)
+			if exact || actual.Cmp(root) != 0 {
+				t.Fatalf("Expected inexact root %s, but got %s (%t)", root, actual, exact)
+			}
+		}
+	}
+	if actual, exact := Root(big.NewInt(0), 3); !exact || actual.Sign() != 0 {
+		t.Fatalf("Expected exact root 0, but got %s (%t)", actual, exact)
+	}
+}
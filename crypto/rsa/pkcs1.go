@@ -0,0 +1,98 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+const (
+	// minPadding is the minimum number of non-zero padding bytes required by PKCS#1 v1.5
+	minPadding = 8
+)
+
+var (
+	InvalidPaddingErr = errors.New("invalid PKCS#1 v1.5 padding")
+)
+
+// PadPkcs1v15 pads the message for encryption to a block of the given size: 00 02 || non-zero random bytes || 00 || msg
+func PadPkcs1v15(msg []byte, size int) ([]byte, error) {
+	if len(msg) > size-3-minPadding {
+		return nil, MessageTooLargeErr
+	}
+	res := make([]byte, size)
+	res[1] = 0x02
+	ps := res[2 : size-len(msg)-1]
+	if _, err := io.ReadFull(rand.Reader, ps); err != nil {
+		return nil, err
+	}
+	for i := range ps {
+		for ps[i] == 0 {
+			if _, err := io.ReadFull(rand.Reader, ps[i:i+1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	copy(res[size-len(msg):], msg)
+	return res, nil
+}
+
+// UnpadPkcs1v15 strictly checks the encryption padding and returns the message
+func UnpadPkcs1v15(em []byte) ([]byte, error) {
+	if !Strict.conforms(em) {
+		return nil, InvalidPaddingErr
+	}
+	for i := 2; i < len(em); i++ {
+		if em[i] == 0 {
+			return em[i+1:], nil
+		}
+	}
+	return nil, InvalidPaddingErr
+}
+
+// Conformance configures which properties of the padding a padding oracle checks
+type Conformance struct {
+	// Separator requires a zero byte after the padding string
+	Separator bool
+	// MinPadding is the minimum number of non-zero padding bytes before the separator
+	MinPadding int
+}
+
+var (
+	// Loose only checks that the plaintext starts with 00 02
+	Loose = Conformance{}
+	// Strict checks the padding as described in PKCS#1 v1.5
+	Strict = Conformance{
+		Separator:  true,
+		MinPadding: minPadding,
+	}
+)
+
+func (c Conformance) conforms(em []byte) bool {
+	if len(em) < 2 || em[0] != 0x00 || em[1] != 0x02 {
+		return false
+	}
+	if !c.Separator {
+		return true
+	}
+	for i := 2; i < len(em); i++ {
+		if em[i] == 0 {
+			return i-2 >= c.MinPadding
+		}
+	}
+	return false
+}
+
+// PaddingOracle reports whether the decryption of the ciphertext is PKCS#1 v1.5 conforming
+type PaddingOracle func(ct []byte) (bool, error)
+
+// NewPaddingOracle returns an oracle that checks the padding of the decrypted ciphertext according to the conformance
+func NewPaddingOracle(k *PrivateKey, c Conformance) PaddingOracle {
+	return func(ct []byte) (bool, error) {
+		em, err := k.DecryptBytes(ct)
+		if err != nil {
+			return false, err
+		}
+		return c.conforms(em), nil
+	}
+}
@@ -0,0 +1,160 @@
+package rsa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPadPkcs1v15(t *testing.T) {
+	msg := []byte("kick it, CC")
+	em, err := PadPkcs1v15(msg, 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(em) != 32 || em[0] != 0x00 || em[1] != 0x02 {
+		t.Fatalf("Expected block starting with 00 02, but got %x", em)
+	}
+	if bytes.IndexByte(em[2:], 0) != 32-len(msg)-3 {
+		t.Fatalf("Expected non-zero padding, but got %x", em)
+	}
+	actual, err := UnpadPkcs1v15(em)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(msg, actual) {
+		t.Fatalf("Expected %q, but got %q", msg, actual)
+	}
+
+	if _, err := PadPkcs1v15(make([]byte, 22), 32); err != MessageTooLargeErr {
+		t.Fatalf("Expected error %s, but got %v", MessageTooLargeErr, err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	tests := []struct {
+		name   string
+		em     []byte
+		loose  bool
+		strict bool
+	}{
+		{
+			name:   "Valid",
+			em:     []byte{0, 2, 1, 2, 3, 4, 5, 6, 7, 8, 0, 'a'},
+			loose:  true,
+			strict: true,
+		},
+		{
+			name:  "Short padding",
+			em:    []byte{0, 2, 1, 2, 3, 0, 'a', 'b', 'c', 'd', 'e', 'f'},
+			loose: true,
+		},
+		{
+			name:  "No separator",
+			em:    []byte{0, 2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			loose: true,
+		},
+		{
+			name: "Wrong block type",
+			em:   []byte{0, 1, 1, 2, 3, 4, 5, 6, 7, 8, 0, 'a'},
+		},
+		{
+			name: "Leading non-zero",
+			em:   []byte{1, 2, 1, 2, 3, 4, 5, 6, 7, 8, 0, 'a'},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Loose.conforms(tt.em); actual != tt.loose {
+				t.Fatalf("Expected loose conformance %t, but got %t", tt.loose, actual)
+			}
+			if actual := Strict.conforms(tt.em); actual != tt.strict {
+				t.Fatalf("Expected strict conformance %t, but got %t", tt.strict, actual)
+			}
+		})
+	}
+}
+
+func TestBleichenbacher(t *testing.T) {
+	tests := []struct {
+		name  string
+		bits  int
+		c     Conformance
+		short bool
+	}{
+		{
+			name:  "256 bit",
+			bits:  256,
+			c:     Loose,
+			short: true,
+		},
+		{
+			// a strict oracle rejects most 00 02 plaintexts, which takes up to a million queries
+			name: "256 bit strict",
+			bits: 256,
+			c:    Strict,
+		},
+		{
+			name: "768 bit",
+			bits: 768,
+			c:    Loose,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if testing.Short() && !tt.short {
+				t.Skip("skipping large modulus in short mode")
+			}
+			k, err := GenerateKey(tt.bits, 3)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			msg := []byte("kick it, CC")
+			em, err := PadPkcs1v15(msg, k.Size())
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			ct, err := k.EncryptBytes(em)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			res, err := Bleichenbacher(&k.PublicKey, ct, NewPaddingOracle(k, tt.c))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !bytes.Equal(em, res.Plaintext) {
+				t.Fatalf("Expected %x, but got %x", em, res.Plaintext)
+			}
+			if res.Queries == 0 {
+				t.Fatalf("Expected queries to be counted")
+			}
+			actual, err := UnpadPkcs1v15(res.Plaintext)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !bytes.Equal(msg, actual) {
+				t.Fatalf("Expected %q, but got %q", msg, actual)
+			}
+		})
+	}
+}
+
+func TestBleichenbacherBlinding(t *testing.T) {
+	k, err := GenerateKey(256, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// a ciphertext of a non-conforming plaintext requires blinding first
+	m := []byte("not padded at all")
+	ct, err := k.EncryptBytes(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	res, err := Bleichenbacher(&k.PublicKey, ct, NewPaddingOracle(k, Loose))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(m, BytesToInt(res.Plaintext).Bytes()) {
+		t.Fatalf("Expected %q, but got %q", m, res.Plaintext)
+	}
+}
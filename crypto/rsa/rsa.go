@@ -0,0 +1,309 @@
+// Package rsa implements textbook RSA, without any padding.
+package rsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+const (
+	// mrRounds is the number of Miller-Rabin rounds used when testing for primality
+	mrRounds = 40
+)
+
+var (
+	NoInverseErr       = errors.New("no modular inverse exists")
+	MessageTooLargeErr = errors.New("message is too large for the modulus")
+	InvalidKeySizeErr  = errors.New("invalid key size")
+	InvalidExponentErr = errors.New("invalid public exponent")
+)
+
+var (
+	one   = big.NewInt(1)
+	two   = big.NewInt(2)
+	three = big.NewInt(3)
+)
+
+// smallPrimes are used to quickly rule out candidates before running Miller-Rabin
+var smallPrimes []int64
+
+func init() {
+	sieve := make([]bool, 1000)
+	for i := 2; i < len(sieve); i++ {
+		if sieve[i] {
+			continue
+		}
+		smallPrimes = append(smallPrimes, int64(i))
+		for j := i * i; j < len(sieve); j += i {
+			sieve[j] = true
+		}
+	}
+}
+
+// Egcd returns gcd(a, b) and x, y such that a*x + b*y = gcd(a, b), using the extended Euclidean algorithm
+func Egcd(a, b *big.Int) (*big.Int, *big.Int, *big.Int) {
+	oldR, r := new(big.Int).Set(a), new(big.Int).Set(b)
+	oldS, s := big.NewInt(1), big.NewInt(0)
+	oldT, t := big.NewInt(0), big.NewInt(1)
+	q, tmp := new(big.Int), new(big.Int)
+	for r.Sign() != 0 {
+		q.Div(oldR, r)
+
+		tmp.Mul(q, r)
+		oldR, r = r, new(big.Int).Sub(oldR, tmp)
+		tmp.Mul(q, s)
+		oldS, s = s, new(big.Int).Sub(oldS, tmp)
+		tmp.Mul(q, t)
+		oldT, t = t, new(big.Int).Sub(oldT, tmp)
+	}
+	return oldR, oldS, oldT
+}
+
+// InvMod returns the inverse of a modulo m
+func InvMod(a, m *big.Int) (*big.Int, error) {
+	g, x, _ := Egcd(new(big.Int).Mod(a, m), m)
+	if g.Cmp(one) != 0 {
+		return nil, NoInverseErr
+	}
+	return x.Mod(x, m), nil
+}
+
+// IsPrime performs trial division by small primes followed by the Miller-Rabin test with random bases
+func IsPrime(n *big.Int, rounds int) bool {
+	if n.Cmp(two) < 0 {
+		return false
+	}
+	m := new(big.Int)
+	for _, p := range smallPrimes {
+		bp := big.NewInt(p)
+		if n.Cmp(bp) == 0 {
+			return true
+		}
+		if m.Mod(n, bp).Sign() == 0 {
+			return false
+		}
+	}
+
+	// n - 1 = d * 2^s
+	nm1 := new(big.Int).Sub(n, one)
+	d := new(big.Int).Set(nm1)
+	s := 0
+	for d.Bit(0) == 0 {
+		d.Rsh(d, 1)
+		s++
+	}
+
+	max := new(big.Int).Sub(n, three)
+	for i := 0; i < rounds; i++ {
+		a, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return false
+		}
+		a.Add(a, two)
+
+		x := new(big.Int).Exp(a, d, n)
+		if x.Cmp(one) == 0 || x.Cmp(nm1) == 0 {
+			continue
+		}
+		composite := true
+		for j := 1; j < s; j++ {
+			x.Mul(x, x)
+			x.Mod(x, n)
+			if x.Cmp(nm1) == 0 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// GeneratePrime returns a random prime of exactly the given number of bits
+func GeneratePrime(r io.Reader, bits int) (*big.Int, error) {
+	if bits < 3 {
+		return nil, InvalidKeySizeErr
+	}
+	b := make([]byte, (bits+7)/8)
+	excess := uint(len(b)*8 - bits)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		// clear the excess bits, and set the top two bits so that the product of two primes has twice the bits
+		b[0] &= byte(0xff >> excess)
+		if excess < 7 {
+			b[0] |= 0xc0 >> excess
+		} else {
+			b[0] |= 0x01
+			b[1] |= 0x80
+		}
+		b[len(b)-1] |= 1
+
+		p := new(big.Int).SetBytes(b)
+		if IsPrime(p, mrRounds) {
+			return p, nil
+		}
+	}
+}
+
+type PublicKey struct {
+	N *big.Int
+	E *big.Int
+}
+
+// Size returns the size of the modulus in bytes
+func (k *PublicKey) Size() int {
+	return (k.N.BitLen() + 7) / 8
+}
+
+// Encrypt computes m^e mod n
+func (k *PublicKey) Encrypt(m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(k.N) >= 0 {
+		return nil, MessageTooLargeErr
+	}
+	return new(big.Int).Exp(m, k.E, k.N), nil
+}
+
+// EncryptBytes encrypts the message interpreted as a big-endian integer, and returns the ciphertext of the modulus size
+func (k *PublicKey) EncryptBytes(msg []byte) ([]byte, error) {
+	c, err := k.Encrypt(BytesToInt(msg))
+	if err != nil {
+		return nil, err
+	}
+	return IntToBytes(c, k.Size()), nil
+}
+
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+	P *big.Int
+	Q *big.Int
+
+	// values used for decryption with the Chinese remainder theorem
+	Dp   *big.Int
+	Dq   *big.Int
+	Qinv *big.Int
+}
+
+// GenerateKey generates a key with a modulus of the given size and public exponent e
+func GenerateKey(bits int, e int64) (*PrivateKey, error) {
+	if bits < 16 {
+		return nil, InvalidKeySizeErr
+	}
+	if e < 3 || e%2 == 0 {
+		return nil, InvalidExponentErr
+	}
+	E := big.NewInt(e)
+	for {
+		p, err := GeneratePrime(rand.Reader, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := GeneratePrime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		k, err := NewPrivateKey(p, q, E)
+		if err == NoInverseErr {
+			// e is not coprime with the totient
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if k.N.BitLen() != bits {
+			continue
+		}
+		return k, nil
+	}
+}
+
+// NewPrivateKey derives the private key from the primes p and q and the public exponent e
+func NewPrivateKey(p, q, e *big.Int) (*PrivateKey, error) {
+	pm1 := new(big.Int).Sub(p, one)
+	qm1 := new(big.Int).Sub(q, one)
+	et := new(big.Int).Mul(pm1, qm1)
+
+	d, err := InvMod(e, et)
+	if err != nil {
+		return nil, err
+	}
+	qinv, err := InvMod(q, p)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			N: new(big.Int).Mul(p, q),
+			E: new(big.Int).Set(e),
+		},
+		D:    d,
+		P:    new(big.Int).Set(p),
+		Q:    new(big.Int).Set(q),
+		Dp:   new(big.Int).Mod(d, pm1),
+		Dq:   new(big.Int).Mod(d, qm1),
+		Qinv: qinv,
+	}, nil
+}
+
+// Decrypt computes c^d mod n
+func (k *PrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if c.Sign() < 0 || c.Cmp(k.N) >= 0 {
+		return nil, MessageTooLargeErr
+	}
+	return new(big.Int).Exp(c, k.D, k.N), nil
+}
+
+// DecryptCrt computes c^d mod n using the Chinese remainder theorem, which is roughly four times as fast as Decrypt
+func (k *PrivateKey) DecryptCrt(c *big.Int) (*big.Int, error) {
+	if c.Sign() < 0 || c.Cmp(k.N) >= 0 {
+		return nil, MessageTooLargeErr
+	}
+	m1 := new(big.Int).Exp(c, k.Dp, k.P)
+	m2 := new(big.Int).Exp(c, k.Dq, k.Q)
+
+	// h = qinv * (m1 - m2) mod p
+	h := m1.Sub(m1, m2)
+	h.Mul(h, k.Qinv)
+	h.Mod(h, k.P)
+
+	// m = m2 + h * q
+	h.Mul(h, k.Q)
+	return h.Add(h, m2), nil
+}
+
+// DecryptBytes decrypts the ciphertext interpreted as a big-endian integer, and returns the plaintext of the modulus size
+func (k *PrivateKey) DecryptBytes(ct []byte) ([]byte, error) {
+	m, err := k.DecryptCrt(BytesToInt(ct))
+	if err != nil {
+		return nil, err
+	}
+	return IntToBytes(m, k.Size()), nil
+}
+
+// BytesToInt interprets the bytes as a big-endian unsigned integer
+func BytesToInt(b []byte) *big.Int {
+	return new(big.Int).SetBytes(b)
+}
+
+// IntToBytes returns the big-endian representation of i, left-padded with zeroes to size bytes.
+// Zero is encoded as a single zero byte when size is smaller than one, and i is never truncated.
+func IntToBytes(i *big.Int, size int) []byte {
+	b := i.Bytes()
+	if size < 1 {
+		size = 1
+	}
+	if len(b) >= size {
+		return b
+	}
+	res := make([]byte, size)
+	copy(res[size-len(b):], b)
+	return res
+}
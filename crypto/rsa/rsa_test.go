@@ -0,0 +1,255 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+)
+
+func TestEgcd(t *testing.T) {
+	r := mrand.New(mrand.NewSource(1))
+	max := new(big.Int).Lsh(one, 256)
+	for i := 0; i < 100; i++ {
+		a := new(big.Int).Rand(r, max)
+		b := new(big.Int).Rand(r, max)
+		g, x, y := Egcd(a, b)
+		if expected := new(big.Int).GCD(nil, nil, a, b); expected.Cmp(g) != 0 {
+			t.Fatalf("Expected gcd %s, but got %s", expected, g)
+		}
+		sum := new(big.Int).Add(new(big.Int).Mul(a, x), new(big.Int).Mul(b, y))
+		if sum.Cmp(g) != 0 {
+			t.Fatalf("Expected a*x + b*y = %s, but got %s", g, sum)
+		}
+	}
+}
+
+func TestInvMod(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, m     int64
+		expected int64
+		err      error
+	}{
+		{
+			name:     "Cryptopals example",
+			a:        17,
+			m:        3120,
+			expected: 2753,
+		},
+		{
+			name:     "Negative input",
+			a:        -3,
+			m:        7,
+			expected: 2,
+		},
+		{
+			name: "No inverse",
+			a:    6,
+			m:    9,
+			err:  NoInverseErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := InvMod(big.NewInt(tt.a), big.NewInt(tt.m))
+			if err != tt.err {
+				t.Fatalf("Expected error %v, but got %v", tt.err, err)
+			}
+			if err == nil && actual.Int64() != tt.expected {
+				t.Fatalf("Expected %d, but got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsPrime(t *testing.T) {
+	for i := int64(0); i < 5000; i++ {
+		n := big.NewInt(i)
+		if expected, actual := n.ProbablyPrime(20), IsPrime(n, 20); expected != actual {
+			t.Fatalf("Expected %t for %d, but got %t", expected, i, actual)
+		}
+	}
+	// Carmichael numbers fool the Fermat test, but not Miller-Rabin
+	for _, c := range []int64{561, 41041, 825265, 321197185} {
+		if IsPrime(big.NewInt(c), 20) {
+			t.Fatalf("Expected Carmichael number %d to be composite", c)
+		}
+	}
+}
+
+func TestGeneratePrime(t *testing.T) {
+	for _, bits := range []int{8, 17, 64, 128, 512} {
+		p, err := GeneratePrime(rand.Reader, bits)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if p.BitLen() != bits {
+			t.Fatalf("Expected %d bits, but got %d", bits, p.BitLen())
+		}
+		if !p.ProbablyPrime(20) {
+			t.Fatalf("Expected %s to be prime", p)
+		}
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	for _, bits := range []int{64, 256, 1024} {
+		k, err := GenerateKey(bits, 3)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if k.N.BitLen() != bits {
+			t.Fatalf("Expected %d bit modulus, but got %d", bits, k.N.BitLen())
+		}
+		m := big.NewInt(42)
+		c, err := k.Encrypt(m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for _, decrypt := range []func(*big.Int) (*big.Int, error){k.Decrypt, k.DecryptCrt} {
+			actual, err := decrypt(c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if actual.Cmp(m) != 0 {
+				t.Fatalf("Expected %s, but got %s", m, actual)
+			}
+		}
+	}
+	if _, err := GenerateKey(1024, 4); err != InvalidExponentErr {
+		t.Fatalf("Expected error %s, but got %v", InvalidExponentErr, err)
+	}
+}
+
+func TestEncryptTooLarge(t *testing.T) {
+	k, err := GenerateKey(128, 65537)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := k.Encrypt(k.N); err != MessageTooLargeErr {
+		t.Fatalf("Expected error %s, but got %v", MessageTooLargeErr, err)
+	}
+}
+
+func fromStd(k *stdrsa.PrivateKey) (*PrivateKey, error) {
+	return NewPrivateKey(k.Primes[0], k.Primes[1], big.NewInt(int64(k.E)))
+}
+
+// TestStd cross-checks the raw operations against the padded operations of crypto/rsa
+func TestStd(t *testing.T) {
+	std, err := stdrsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	k, err := fromStd(std)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if k.N.Cmp(std.N) != 0 {
+		t.Fatalf("Expected modulus %s, but got %s", std.N, k.N)
+	}
+	// d may differ by a multiple of lcm(p-1, q-1), but the CRT exponents may not
+	if k.Dp.Cmp(std.Precomputed.Dp) != 0 || k.Dq.Cmp(std.Precomputed.Dq) != 0 || k.Qinv.Cmp(std.Precomputed.Qinv) != 0 {
+		t.Fatalf("Expected CRT values to match crypto/rsa")
+	}
+
+	msg := []byte("cross-check with crypto/rsa")
+
+	t.Run("Decrypt", func(t *testing.T) {
+		ct, err := stdrsa.EncryptPKCS1v15(rand.Reader, &std.PublicKey, msg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pt, err := k.DecryptBytes(ct)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if pt[0] != 0x00 || pt[1] != 0x02 || !bytes.HasSuffix(pt, append([]byte{0x00}, msg...)) {
+			t.Fatalf("Expected padded plaintext, but got %x", pt)
+		}
+	})
+
+	t.Run("Sign", func(t *testing.T) {
+		digest := sha256.Sum256(msg)
+		sig, err := stdrsa.SignPKCS1v15(rand.Reader, std, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		// encrypting the signature reveals the padded digest, which decrypts back to the signature
+		em, err := k.EncryptBytes(sig)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if em[0] != 0x00 || em[1] != 0x01 || !bytes.HasSuffix(em, digest[:]) {
+			t.Fatalf("Expected padded digest, but got %x", em)
+		}
+		actual, err := k.DecryptBytes(em)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !bytes.Equal(sig, actual) {
+			t.Fatalf("Expected signature %x, but got %x", sig, actual)
+		}
+		plain, err := k.Decrypt(BytesToInt(em))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !bytes.Equal(sig, IntToBytes(plain, k.Size())) {
+			t.Fatalf("Expected non-CRT decryption to match the signature")
+		}
+	})
+}
+
+func TestIntToBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *big.Int
+		size     int
+		expected []byte
+	}{
+		{
+			name:     "Zero",
+			input:    big.NewInt(0),
+			expected: []byte{0},
+		},
+		{
+			name:     "Zero padded",
+			input:    big.NewInt(0),
+			size:     3,
+			expected: []byte{0, 0, 0},
+		},
+		{
+			name:     "Padded",
+			input:    big.NewInt(0x0102),
+			size:     4,
+			expected: []byte{0, 0, 1, 2},
+		},
+		{
+			name:     "Larger than size",
+			input:    big.NewInt(0x010203),
+			size:     2,
+			expected: []byte{1, 2, 3},
+		},
+		{
+			name:     "Larger than uint64",
+			input:    new(big.Int).Lsh(one, 64),
+			expected: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := IntToBytes(tt.input, tt.size)
+			if !bytes.Equal(tt.expected, actual) {
+				t.Fatalf("Expected %x, but got %x", tt.expected, actual)
+			}
+			if BytesToInt(actual).Cmp(tt.input) != 0 {
+				t.Fatalf("Expected round trip to %s", tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"hash"
+	"math/big"
+)
+
+var (
+	InvalidSignatureErr = errors.New("invalid signature")
+	NoForgeryErr        = errors.New("no forgery fits in the modulus")
+)
+
+// Digest is a hash function together with the ASN.1 DigestInfo prefix that identifies it in a signature
+type Digest struct {
+	Name   string
+	New    func() hash.Hash
+	Prefix []byte
+}
+
+var (
+	Sha1 = Digest{
+		Name:   "sha1",
+		New:    sha1.New,
+		Prefix: []byte{0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	}
+	Sha256 = Digest{
+		Name:   "sha256",
+		New:    sha256.New,
+		Prefix: []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	}
+)
+
+// sum returns the DigestInfo of the message
+func (d Digest) sum(msg []byte) []byte {
+	h := d.New()
+	h.Write(msg)
+	return h.Sum(append([]byte{}, d.Prefix...))
+}
+
+// SignPkcs1v15 signs the digest of the message, padded as 00 01 FF..FF 00 || DigestInfo
+func (k *PrivateKey) SignPkcs1v15(d Digest, msg []byte) ([]byte, error) {
+	size := k.Size()
+	info := d.sum(msg)
+	if len(info) > size-3-minPadding {
+		return nil, MessageTooLargeErr
+	}
+	em := make([]byte, size)
+	em[1] = 0x01
+	for i := 2; i < size-len(info)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[size-len(info):], info)
+	return k.DecryptBytes(em)
+}
+
+// Verifier checks PKCS#1 v1.5 signatures. Its options reproduce the mistakes of sloppy verifiers.
+type Verifier struct {
+	// MinPadding is the minimum number of padding bytes
+	MinPadding int
+	// AnyPadding accepts any non-zero padding bytes instead of only FF
+	AnyPadding bool
+	// TrailingGarbage accepts bytes after the digest, i.e. it does not check that the digest is right-aligned
+	TrailingGarbage bool
+}
+
+var (
+	// StrictVerifier checks signatures as described in PKCS#1 v1.5
+	StrictVerifier = Verifier{
+		MinPadding: minPadding,
+	}
+	// LaxVerifier parses the signature from left to right and stops after the digest
+	LaxVerifier = Verifier{
+		MinPadding:      1,
+		TrailingGarbage: true,
+	}
+)
+
+// Verify checks the signature of the message under the public key
+func (v Verifier) Verify(pub *PublicKey, d Digest, msg, sig []byte) error {
+	if len(sig) != pub.Size() {
+		return InvalidSignatureErr
+	}
+	m, err := pub.Encrypt(BytesToInt(sig))
+	if err != nil {
+		return InvalidSignatureErr
+	}
+	em := IntToBytes(m, pub.Size())
+	if em[0] != 0x00 || em[1] != 0x01 {
+		return InvalidSignatureErr
+	}
+
+	i := 2
+	for i < len(em) && (em[i] == 0xff || v.AnyPadding && em[i] != 0x00) {
+		i++
+	}
+	if i-2 < v.MinPadding || i == len(em) {
+		return InvalidSignatureErr
+	}
+	// skip the zero separator
+	i++
+
+	info := d.sum(msg)
+	if !bytes.HasPrefix(em[i:], info) {
+		return InvalidSignatureErr
+	}
+	if !v.TrailingGarbage && i+len(info) != len(em) {
+		return InvalidSignatureErr
+	}
+	return nil
+}
+
+// ForgeCubeRoot forges a signature for verifiers that do not check that the digest is right-aligned, using
+// Bleichenbacher's attack on small public exponents. The forged block is 00 01 FF..FF 00 || DigestInfo || garbage,
+// in which the garbage is chosen such that the block is a perfect e-th power over the integers.
+func ForgeCubeRoot(pub *PublicKey, d Digest, msg []byte, padding int) ([]byte, error) {
+	if !pub.E.IsInt64() {
+		return nil, InvalidExponentErr
+	}
+	e := int(pub.E.Int64())
+	size := pub.Size()
+	info := d.sum(msg)
+	garbage := size - 3 - padding - len(info)
+	if padding < 0 || garbage < 0 {
+		return nil, MessageTooLargeErr
+	}
+
+	lo := make([]byte, size)
+	lo[1] = 0x01
+	for i := 0; i < padding; i++ {
+		lo[2+i] = 0xff
+	}
+	copy(lo[3+padding:], info)
+	hi := append([]byte{}, lo...)
+	for i := size - garbage; i < size; i++ {
+		hi[i] = 0xff
+	}
+
+	// the largest e-th power below the block filled with FF garbage must not be below the block with zero garbage
+	r, _ := Root(BytesToInt(hi), e)
+	if new(big.Int).Exp(r, pub.E, nil).Cmp(BytesToInt(lo)) < 0 {
+		return nil, NoForgeryErr
+	}
+	return IntToBytes(r, size), nil
+}
@@ -0,0 +1,167 @@
+package rsa
+
+import (
+	"crypto"
+	stdrsa "crypto/rsa"
+	"crypto/sha256"
+	"testing"
+)
+
+var (
+	signMsg = []byte("hi mom")
+)
+
+func TestSignPkcs1v15(t *testing.T) {
+	k, err := GenerateKey(1024, 65537)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	sig, err := k.SignPkcs1v15(Sha256, signMsg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	digest := sha256.Sum256(signMsg)
+	std := &stdrsa.PublicKey{N: k.N, E: int(k.E.Int64())}
+	if err := stdrsa.VerifyPKCS1v15(std, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("Expected crypto/rsa to accept the signature, but got %s", err)
+	}
+	for _, v := range []Verifier{StrictVerifier, LaxVerifier} {
+		if err := v.Verify(&k.PublicKey, Sha256, signMsg, sig); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := v.Verify(&k.PublicKey, Sha256, []byte("hi dad"), sig); err != InvalidSignatureErr {
+			t.Fatalf("Expected error %s, but got %v", InvalidSignatureErr, err)
+		}
+	}
+}
+
+// block builds 00 || bt || padding || 00 || DigestInfo || garbage, filling the garbage up to the key size
+func block(size int, bt byte, padding []byte, info []byte) []byte {
+	em := append([]byte{0x00, bt}, padding...)
+	em = append(em, 0x00)
+	em = append(em, info...)
+	for len(em) < size {
+		em = append(em, 0x42)
+	}
+	return em
+}
+
+func ffs(n int) []byte {
+	res := make([]byte, n)
+	for i := range res {
+		res[i] = 0xff
+	}
+	return res
+}
+
+// TestLaxVerifiers is a regression suite of the malformed signatures that sloppy verifiers accept
+func TestLaxVerifiers(t *testing.T) {
+	k, err := GenerateKey(1024, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	size := k.Size()
+	info := Sha1.sum(signMsg)
+	full := size - 3 - len(info)
+	other := Sha1.sum([]byte("hi dad"))
+
+	verifiers := map[string]Verifier{
+		"strict":      StrictVerifier,
+		"lax":         LaxVerifier,
+		"garbage":     {MinPadding: minPadding, TrailingGarbage: true},
+		"any padding": {MinPadding: minPadding, AnyPadding: true},
+	}
+	nonFf := ffs(full)
+	nonFf[10] = 0x13
+
+	tests := []struct {
+		name     string
+		em       []byte
+		accepted []string
+	}{
+		{
+			name:     "Valid",
+			em:       block(size, 0x01, ffs(full), info),
+			accepted: []string{"strict", "lax", "garbage", "any padding"},
+		},
+		{
+			name:     "Trailing garbage",
+			em:       block(size, 0x01, ffs(full-10), info),
+			accepted: []string{"lax", "garbage"},
+		},
+		{
+			name:     "Single padding byte",
+			em:       block(size, 0x01, ffs(1), info),
+			accepted: []string{"lax"},
+		},
+		{
+			name:     "Non-FF padding",
+			em:       block(size, 0x01, nonFf, info),
+			accepted: []string{"any padding"},
+		},
+		{
+			name: "Wrong digest",
+			em:   block(size, 0x01, ffs(full), other),
+		},
+		{
+			name: "Wrong block type",
+			em:   block(size, 0x02, ffs(full), info),
+		},
+		{
+			name: "No padding",
+			em:   block(size, 0x01, nil, info),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := k.DecryptBytes(tt.em)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			for name, v := range verifiers {
+				expected := false
+				for _, a := range tt.accepted {
+					expected = expected || a == name
+				}
+				err := v.Verify(&k.PublicKey, Sha1, signMsg, sig)
+				if actual := err == nil; actual != expected {
+					t.Fatalf("Expected %s verifier to accept: %t, but got error %v", name, expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestForgeCubeRoot(t *testing.T) {
+	tests := []struct {
+		digest Digest
+		bits   int
+	}{
+		{Sha1, 1024},
+		// the longer DigestInfo leaves too little garbage in a 1024-bit block
+		{Sha256, 2048},
+	}
+	for _, tt := range tests {
+		t.Run(tt.digest.Name, func(t *testing.T) {
+			k, err := GenerateKey(tt.bits, 3)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			sig, err := ForgeCubeRoot(&k.PublicKey, tt.digest, signMsg, 4)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err := LaxVerifier.Verify(&k.PublicKey, tt.digest, signMsg, sig); err != nil {
+				t.Fatalf("Expected lax verifier to accept forgery, but got %s", err)
+			}
+			if err := StrictVerifier.Verify(&k.PublicKey, tt.digest, signMsg, sig); err != InvalidSignatureErr {
+				t.Fatalf("Expected error %s, but got %v", InvalidSignatureErr, err)
+			}
+
+			// too much padding leaves too little garbage to find a cube
+			if _, err := ForgeCubeRoot(&k.PublicKey, tt.digest, signMsg, k.Size()/2); err != NoForgeryErr {
+				t.Fatalf("Expected error %s, but got %v", NoForgeryErr, err)
+			}
+		})
+	}
+}
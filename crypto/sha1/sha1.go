@@ -0,0 +1,113 @@
+// Package sha1 implements SHA-1 with access to its internal state, for length extension attacks.
+package sha1
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto/internal/md"
+	"hash"
+	"math/bits"
+)
+
+const (
+	Size      = 20
+	BlockSize = md.BlockSize
+)
+
+var (
+	InitialState = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+	InvalidDigestSizeErr = errors.New("digest must be 20 bytes")
+	InvalidLengthErr     = md.InvalidLengthErr
+)
+
+// New returns a SHA-1 hash starting from the standard initial state
+func New() hash.Hash {
+	return NewFromState(InitialState, 0)
+}
+
+// NewFromState returns a SHA-1 hash that continues from the given internal state,
+// as if length bytes (including padding) have already been processed
+func NewFromState(h [5]uint32, length uint64) hash.Hash {
+	return md.New(h[:], length, binary.BigEndian, block)
+}
+
+// NewFromDigest returns a SHA-1 hash whose internal state is taken from a digest,
+// as if length bytes (including padding) have already been processed
+func NewFromDigest(sum []byte, length uint64) (hash.Hash, error) {
+	h, err := State(sum)
+	if err != nil {
+		return nil, err
+	}
+	if length%BlockSize != 0 {
+		return nil, InvalidLengthErr
+	}
+	return NewFromState(h, length), nil
+}
+
+// State returns the internal state that corresponds to a digest
+func State(sum []byte) ([5]uint32, error) {
+	var h [5]uint32
+	if len(sum) != Size {
+		return h, InvalidDigestSizeErr
+	}
+	md.Decode(h[:], sum, binary.BigEndian)
+	return h, nil
+}
+
+// Pad returns the padding that SHA-1 appends to a message of the given length in bytes
+func Pad(length uint64) []byte {
+	return md.Pad(length, binary.BigEndian)
+}
+
+func Sum(data []byte) [Size]byte {
+	var res [Size]byte
+	h := New()
+	h.Write(data)
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+func block(h []uint32, p []byte) {
+	var w [80]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+
+	a, b, c, d, e := h[0], h[1], h[2], h[3], h[4]
+	for i := 0; i < 80; i++ {
+		var f, k uint32
+		switch {
+		case i < 20:
+			f, k = b&c|^b&d, 0x5a827999
+		case i < 40:
+			f, k = b^c^d, 0x6ed9eba1
+		case i < 60:
+			f, k = b&c|b&d|c&d, 0x8f1bbcdc
+		default:
+			f, k = b^c^d, 0xca62c1d6
+		}
+		tmp := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+		a, b, c, d, e = tmp, a, bits.RotateLeft32(b, 30), c, d
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
+}
+
+// Extender exposes the functions needed for length extension attacks on SHA-1
+type Extender struct{}
+
+func (Extender) Pad(length uint64) []byte {
+	return Pad(length)
+}
+
+func (Extender) FromDigest(sum []byte, length uint64) (hash.Hash, error) {
+	return NewFromDigest(sum, length)
+}
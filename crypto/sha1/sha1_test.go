@@ -0,0 +1,100 @@
+package sha1
+
+import (
+	"bytes"
+	stdsha1 "crypto/sha1"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Empty",
+			input:    "",
+			expected: "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		},
+		{
+			name:     "abc",
+			input:    "abc",
+			expected: "a9993e364706816aba3e25717850c26c9cd0d89d",
+		},
+		{
+			name:     "Two blocks",
+			input:    "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq",
+			expected: "84983e441c3bd26ebaae4aa1f95129e5e54670f1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Sum([]byte(tt.input))
+			if hex.EncodeToString(actual[:]) != tt.expected {
+				t.Fatalf("Expected digest %s, but got %x", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDifferential(t *testing.T) {
+	for size := 0; size < 300; size++ {
+		data := make([]byte, size)
+		rand.Read(data)
+
+		h := New()
+		// write in uneven chunks to exercise buffering
+		for i := 0; i < len(data); i += 7 {
+			end := i + 7
+			if end > len(data) {
+				end = len(data)
+			}
+			h.Write(data[i:end])
+		}
+		expected := stdsha1.Sum(data)
+		if actual := h.Sum(nil); !bytes.Equal(expected[:], actual) {
+			t.Fatalf("Expected digest %x for %d bytes, but got %x", expected, size, actual)
+		}
+	}
+}
+
+func TestNewFromDigest(t *testing.T) {
+	msg := []byte("some kind of somewhat long plain text!")
+	extension := []byte(";admin=true")
+	sum := Sum(msg)
+
+	glue := Pad(uint64(len(msg)))
+	h, err := NewFromDigest(sum[:], uint64(len(msg)+len(glue)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	h.Write(extension)
+
+	full := append(append(append([]byte{}, msg...), glue...), extension...)
+	expected := stdsha1.Sum(full)
+	if actual := h.Sum(nil); !bytes.Equal(expected[:], actual) {
+		t.Fatalf("Expected digest %x, but got %x", expected, actual)
+	}
+
+	if _, err := NewFromDigest(sum[:19], 64); err != InvalidDigestSizeErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidDigestSizeErr, err)
+	}
+	if _, err := NewFromDigest(sum[:], 63); err != InvalidLengthErr {
+		t.Fatalf("Expected error %s, but got %s", InvalidLengthErr, err)
+	}
+}
+
+func TestPad(t *testing.T) {
+	for length := uint64(0); length < 200; length++ {
+		pad := Pad(length)
+		if (length+uint64(len(pad)))%BlockSize != 0 {
+			t.Fatalf("Expected padded length of %d bytes to be a multiple of %d, but got %d", length, BlockSize, length+uint64(len(pad)))
+		}
+		if pad[0] != 0x80 {
+			t.Fatalf("Expected padding to start with %x, but got %x", 0x80, pad[0])
+		}
+	}
+}
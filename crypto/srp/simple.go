@@ -0,0 +1,111 @@
+package srp
+
+import (
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"math/big"
+	"net"
+)
+
+// SimpleServer runs simplified SRP, in which B = g^b and u is a random number that does not depend on A and B
+type SimpleServer struct {
+	*Server
+}
+
+func NewSimpleServer(params Params) *SimpleServer {
+	return &SimpleServer{NewServer(params)}
+}
+
+// Handle runs the server side of a single simplified SRP login
+func (s *SimpleServer) Handle(conn net.Conn) error {
+	c := newCodec(conn)
+	p := s.params
+
+	var h hello
+	if err := c.receive(&h); err != nil {
+		return err
+	}
+	u, ok := s.users[h.Email]
+	if !ok || h.A == nil {
+		c.send(result{false})
+		return UnknownUserErr
+	}
+
+	b, err := randomInt(p.N)
+	if err != nil {
+		return err
+	}
+	uR, err := randomInt(new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	B := new(big.Int).Exp(p.G, b, p.N)
+	if err := c.send(challenge{Salt: u.salt, B: B, U: uR}); err != nil {
+		return err
+	}
+
+	// u is the random 128-bit value sent along with B, not a hash, so B no longer depends on v
+	S := new(big.Int).Exp(u.v, uR, p.N)
+	S.Mul(S, h.A)
+	S.Exp(S, b, p.N)
+
+	var pr proof
+	if err := c.receive(&pr); err != nil {
+		return err
+	}
+	ok = crypto.ConstantTimeCompare(Mac(S, u.salt), pr.Mac)
+	return c.send(result{ok})
+}
+
+// SimpleLogin runs the client side of simplified SRP with the password
+func SimpleLogin(conn net.Conn, p Params, email, password string) error {
+	a, err := randomInt(p.N)
+	if err != nil {
+		return err
+	}
+	A := new(big.Int).Exp(p.G, a, p.N)
+
+	return login(conn, email, A, func(ch challenge) (*big.Int, error) {
+		if ch.U == nil {
+			return nil, LoginFailedErr
+		}
+		x := hashInt(ch.Salt, []byte(password))
+
+		// S = B^(a + u * x) mod N
+		exp := new(big.Int).Mul(ch.U, x)
+		exp.Add(exp, a)
+		return new(big.Int).Exp(ch.B, exp, p.N), nil
+	})
+}
+
+// CrackSimple impersonates a simplified SRP server towards a client and cracks the client's password offline.
+// By sending b = 1, B = g, u = 1 and an empty salt, the client's secret becomes S = A * g^x mod N,
+// which can be checked against the client's proof for every password in the word list.
+func CrackSimple(conn net.Conn, p Params, wordlist []string) (string, error) {
+	c := newCodec(conn)
+
+	var h hello
+	if err := c.receive(&h); err != nil {
+		return "", err
+	}
+	salt := []byte{}
+	if err := c.send(challenge{Salt: salt, B: p.G, U: big.NewInt(1)}); err != nil {
+		return "", err
+	}
+	var pr proof
+	if err := c.receive(&pr); err != nil {
+		return "", err
+	}
+	// let the client believe the login failed
+	c.send(result{false})
+
+	for _, password := range wordlist {
+		x := hashInt(salt, []byte(password))
+		S := new(big.Int).Exp(p.G, x, p.N)
+		S.Mul(S, h.A)
+		S.Mod(S, p.N)
+		if crypto.ConstantTimeCompare(Mac(S, salt), pr.Mac) {
+			return password, nil
+		}
+	}
+	return "", LoginFailedErr
+}
@@ -0,0 +1,244 @@
+// Package srp implements Secure Remote Password over a network connection, and attacks on it.
+package srp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/dh"
+	"math/big"
+	"net"
+)
+
+var (
+	LoginFailedErr   = errors.New("login failed")
+	UnknownUserErr   = errors.New("unknown user")
+	InvalidPublicErr = errors.New("invalid public key")
+)
+
+type Params struct {
+	N *big.Int
+	G *big.Int
+	K *big.Int
+}
+
+var (
+	DefaultParams = Params{
+		N: dh.Modp1536.P,
+		G: big.NewInt(2),
+		K: big.NewInt(3),
+	}
+)
+
+// messages exchanged between client and server
+type (
+	hello struct {
+		Email string
+		A     *big.Int
+	}
+	challenge struct {
+		Salt []byte
+		B    *big.Int
+		U    *big.Int `json:",omitempty"`
+	}
+	proof struct {
+		Mac []byte
+	}
+	result struct {
+		Ok bool
+	}
+)
+
+type codec struct {
+	enc *json.Encoder
+	dec *json.Decoder
+}
+
+func newCodec(conn net.Conn) *codec {
+	return &codec{
+		enc: json.NewEncoder(conn),
+		dec: json.NewDecoder(conn),
+	}
+}
+
+func (c *codec) send(v interface{}) error {
+	return c.enc.Encode(v)
+}
+
+func (c *codec) receive(v interface{}) error {
+	return c.dec.Decode(v)
+}
+
+func hashInt(parts ...[]byte) *big.Int {
+	return new(big.Int).SetBytes(hashBytes(parts...))
+}
+
+func hashBytes(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func randomInt(max *big.Int) (*big.Int, error) {
+	return rand.Int(rand.Reader, max)
+}
+
+// Mac returns the proof of knowledge of the shared secret S
+func Mac(s *big.Int, salt []byte) []byte {
+	return crypto.Hmac(sha256.New)(hashBytes(s.Bytes()), salt)
+}
+
+type user struct {
+	salt []byte
+	v    *big.Int
+}
+
+type Server struct {
+	params Params
+	users  map[string]user
+}
+
+func NewServer(params Params) *Server {
+	return &Server{
+		params: params,
+		users:  map[string]user{},
+	}
+}
+
+// Register stores the password verifier for the user
+func (s *Server) Register(email, password string) {
+	salt := crypto.RandomKey(16)
+	x := hashInt(salt, []byte(password))
+	s.users[email] = user{
+		salt: salt,
+		v:    new(big.Int).Exp(s.params.G, x, s.params.N),
+	}
+}
+
+// Handle runs the server side of a single login
+func (s *Server) Handle(conn net.Conn) error {
+	c := newCodec(conn)
+	p := s.params
+
+	var h hello
+	if err := c.receive(&h); err != nil {
+		return err
+	}
+	u, ok := s.users[h.Email]
+	if !ok || h.A == nil {
+		c.send(result{false})
+		return UnknownUserErr
+	}
+
+	b, err := randomInt(p.N)
+	if err != nil {
+		return err
+	}
+	// B = kv + g^b mod N
+	B := new(big.Int).Mul(p.K, u.v)
+	B.Add(B, new(big.Int).Exp(p.G, b, p.N))
+	B.Mod(B, p.N)
+	if err := c.send(challenge{Salt: u.salt, B: B}); err != nil {
+		return err
+	}
+
+	// the server derives u from both public values, then S = (A * v^u)^b mod N
+	uH := hashInt(h.A.Bytes(), B.Bytes())
+	S := new(big.Int).Exp(u.v, uH, p.N)
+	S.Mul(S, h.A)
+	S.Exp(S, b, p.N)
+
+	var pr proof
+	if err := c.receive(&pr); err != nil {
+		return err
+	}
+	ok = crypto.ConstantTimeCompare(Mac(S, u.salt), pr.Mac)
+	return c.send(result{ok})
+}
+
+// Serve handles logins on the listener until it is closed
+func Serve(l net.Listener, handle func(net.Conn) error) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			handle(conn)
+		}()
+	}
+}
+
+// Listen returns a listener on a random loopback port
+func Listen() (net.Listener, error) {
+	return net.Listen("tcp", "127.0.0.1:0")
+}
+
+// login sends the public key, computes the shared secret from the server's challenge and sends the proof
+func login(conn net.Conn, email string, A *big.Int, secret func(challenge) (*big.Int, error)) error {
+	c := newCodec(conn)
+	if err := c.send(hello{email, A}); err != nil {
+		return err
+	}
+	var ch challenge
+	if err := c.receive(&ch); err != nil {
+		return err
+	}
+	if ch.B == nil {
+		return LoginFailedErr
+	}
+	S, err := secret(ch)
+	if err != nil {
+		return err
+	}
+	if err := c.send(proof{Mac(S, ch.Salt)}); err != nil {
+		return err
+	}
+	var r result
+	if err := c.receive(&r); err != nil {
+		return err
+	}
+	if !r.Ok {
+		return LoginFailedErr
+	}
+	return nil
+}
+
+// Login runs the client side of SRP with the password
+func Login(conn net.Conn, p Params, email, password string) error {
+	a, err := randomInt(p.N)
+	if err != nil {
+		return err
+	}
+	A := new(big.Int).Exp(p.G, a, p.N)
+
+	return login(conn, email, A, func(ch challenge) (*big.Int, error) {
+		if new(big.Int).Mod(ch.B, p.N).Sign() == 0 {
+			return nil, InvalidPublicErr
+		}
+		uH := hashInt(A.Bytes(), ch.B.Bytes())
+		x := hashInt(ch.Salt, []byte(password))
+
+		// S = (B - k * g^x)^(a + u * x) mod N
+		base := new(big.Int).Exp(p.G, x, p.N)
+		base.Mul(base, p.K)
+		base.Sub(ch.B, base)
+		base.Mod(base, p.N)
+		exp := new(big.Int).Mul(uH, x)
+		exp.Add(exp, a)
+		return new(big.Int).Exp(base, exp, p.N), nil
+	})
+}
+
+// ZeroKeyLogin logs in without the password by sending A = multiple * N, which forces the server's shared secret to 0
+func ZeroKeyLogin(conn net.Conn, p Params, email string, multiple int64) error {
+	A := new(big.Int).Mul(p.N, big.NewInt(multiple))
+	return login(conn, email, A, func(ch challenge) (*big.Int, error) {
+		return big.NewInt(0), nil
+	})
+}
@@ -0,0 +1,109 @@
+package srp
+
+import (
+	"net"
+	"testing"
+)
+
+func serve(t *testing.T, handle func(net.Conn) error) net.Listener {
+	l, err := Listen()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	go Serve(l, handle)
+	return l
+}
+
+func dial(t *testing.T, l net.Listener) net.Conn {
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return conn
+}
+
+func TestLogin(t *testing.T) {
+	s := NewServer(DefaultParams)
+	s.Register("alice@example.com", "hunter2")
+	l := serve(t, s.Handle)
+	defer l.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		expected error
+	}{
+		{
+			name:     "Correct password",
+			password: "hunter2",
+		},
+		{
+			name:     "Wrong password",
+			password: "hunter3",
+			expected: LoginFailedErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, l)
+			defer conn.Close()
+			if err := Login(conn, DefaultParams, "alice@example.com", tt.password); err != tt.expected {
+				t.Fatalf("Expected error %v, but got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestZeroKeyLogin(t *testing.T) {
+	s := NewServer(DefaultParams)
+	s.Register("alice@example.com", "a password nobody will guess")
+	l := serve(t, s.Handle)
+	defer l.Close()
+
+	for _, multiple := range []int64{0, 1, 2, 5} {
+		conn := dial(t, l)
+		if err := ZeroKeyLogin(conn, DefaultParams, "alice@example.com", multiple); err != nil {
+			t.Fatalf("Expected login with A = %d * N to succeed, but got %s", multiple, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestSimpleLogin(t *testing.T) {
+	s := NewSimpleServer(DefaultParams)
+	s.Register("alice@example.com", "hunter2")
+	l := serve(t, s.Handle)
+	defer l.Close()
+
+	conn := dial(t, l)
+	defer conn.Close()
+	if err := SimpleLogin(conn, DefaultParams, "alice@example.com", "hunter2"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	other := dial(t, l)
+	defer other.Close()
+	if err := SimpleLogin(other, DefaultParams, "alice@example.com", "hunter3"); err != LoginFailedErr {
+		t.Fatalf("Expected error %s, but got %v", LoginFailedErr, err)
+	}
+}
+
+func TestCrackSimple(t *testing.T) {
+	wordlist := []string{"password", "123456", "letmein", "hunter2", "dragon"}
+	found := make(chan string, 1)
+	l := serve(t, func(conn net.Conn) error {
+		password, err := CrackSimple(conn, DefaultParams, wordlist)
+		found <- password
+		return err
+	})
+	defer l.Close()
+
+	conn := dial(t, l)
+	defer conn.Close()
+	if err := SimpleLogin(conn, DefaultParams, "alice@example.com", "hunter2"); err != LoginFailedErr {
+		t.Fatalf("Expected error %s, but got %v", LoginFailedErr, err)
+	}
+	if password := <-found; password != "hunter2" {
+		t.Fatalf("Expected password %s, but got %s", "hunter2", password)
+	}
+}
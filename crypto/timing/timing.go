@@ -0,0 +1,163 @@
+// Package timing implements the HMAC timing leak attack against a web server that compares signatures byte by byte.
+package timing
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"time"
+)
+
+var (
+	NoSignatureFoundErr = errors.New("failed to find a valid signature")
+	InvalidSizeErr      = errors.New("signature size must be between 1 and the HMAC-SHA1 size")
+)
+
+// NewServer returns a local server that responds with status 200 to requests to /test?file=...&signature=...
+// that carry a valid HMAC-SHA1 signature of the file name, truncated to size bytes, and with 500 otherwise.
+// Signatures are compared with an early exit, sleeping for delay after every matching byte.
+func NewServer(key []byte, size int, delay time.Duration) (*httptest.Server, error) {
+	if size < 1 || size > sha1.Size {
+		return nil, InvalidSizeErr
+	}
+	mac := crypto.Hmac(sha1.New)
+	compare := crypto.InsecureCompare(delay)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		file := r.URL.Query().Get("file")
+		sig, err := hex.DecodeString(r.URL.Query().Get("signature"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !compare(mac(key, []byte(file))[:size], sig) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(mux), nil
+}
+
+// Oracle reports whether the signature of the file is valid and how long it took to find out
+type Oracle func(file string, sig []byte) (bool, time.Duration, error)
+
+// HttpOracle returns an oracle that queries the server at the given base URL
+func HttpOracle(client *http.Client, base string) Oracle {
+	return func(file string, sig []byte) (bool, time.Duration, error) {
+		q := url.Values{}
+		q.Set("file", file)
+		q.Set("signature", hex.EncodeToString(sig))
+
+		start := time.Now()
+		resp, err := client.Get(base + "/test?" + q.Encode())
+		elapsed := time.Since(start)
+		if err != nil {
+			return false, 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK, elapsed, nil
+	}
+}
+
+type AttackOpts struct {
+	// Samples is the number of measurements per candidate byte in each round
+	Samples int
+	// MaxRounds is the maximum number of rounds of measurements per byte
+	MaxRounds int
+	// Progress is called with the signature recovered so far after every byte
+	Progress func(sig []byte)
+}
+
+type Result struct {
+	Signature []byte
+	Queries   int
+}
+
+func median(d []time.Duration) time.Duration {
+	s := make([]time.Duration, len(d))
+	copy(s, d)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s[len(s)/2]
+}
+
+// RecoverSignature recovers the signature of a file byte by byte, by picking the byte for which the oracle takes the longest.
+// Each candidate is measured repeatedly and compared on its median, and measurements are repeated until the slowest
+// candidate clearly stands out from the others. The last byte is found by checking which candidate is valid.
+func RecoverSignature(oracle Oracle, file string, size int, opts AttackOpts) (Result, error) {
+	if size < 1 || size > sha1.Size {
+		return Result{}, InvalidSizeErr
+	}
+	if opts.Samples <= 0 {
+		opts.Samples = 1
+	}
+	if opts.MaxRounds <= 0 {
+		opts.MaxRounds = 1
+	}
+
+	res := Result{
+		Signature: make([]byte, size),
+	}
+	for i := 0; i < size-1; i++ {
+		timings := make([][]time.Duration, 256)
+		var best byte
+		for round := 0; round < opts.MaxRounds; round++ {
+			// interleave the candidates, so drift in the timings affects all of them equally
+			for s := 0; s < opts.Samples; s++ {
+				for c := 0; c < 256; c++ {
+					res.Signature[i] = byte(c)
+					_, elapsed, err := oracle(file, res.Signature)
+					if err != nil {
+						return res, err
+					}
+					res.Queries++
+					timings[c] = append(timings[c], elapsed)
+				}
+			}
+
+			medians := make([]time.Duration, 256)
+			for c := range timings {
+				medians[c] = median(timings[c])
+			}
+			order := make([]int, 256)
+			for c := range order {
+				order[c] = c
+			}
+			sort.Slice(order, func(a, b int) bool { return medians[order[a]] > medians[order[b]] })
+			best = byte(order[0])
+
+			// the gap to the runner-up must exceed the spread among the wrong candidates
+			gap := medians[order[0]] - medians[order[1]]
+			spread := medians[order[1]] - medians[order[128]]
+			if gap > 2*spread {
+				break
+			}
+		}
+		res.Signature[i] = best
+		if opts.Progress != nil {
+			opts.Progress(res.Signature[:i+1])
+		}
+	}
+
+	for c := 0; c < 256; c++ {
+		res.Signature[size-1] = byte(c)
+		valid, _, err := oracle(file, res.Signature)
+		if err != nil {
+			return res, err
+		}
+		res.Queries++
+		if valid {
+			if opts.Progress != nil {
+				opts.Progress(res.Signature)
+			}
+			return res, nil
+		}
+	}
+	return res, NoSignatureFoundErr
+}
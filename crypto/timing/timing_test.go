@@ -0,0 +1,65 @@
+package timing
+
+import (
+	"bytes"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"github.com/kdhageman/go-cryptopals/crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	key := crypto.RandomKey(16)
+	srv, err := NewServer(key, sha1.Size, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer srv.Close()
+	oracle := HttpOracle(srv.Client(), srv.URL)
+
+	sig := crypto.Hmac(sha1.New)(key, []byte("foo"))
+	if valid, _, err := oracle("foo", sig); err != nil || !valid {
+		t.Fatalf("Expected valid signature, but got %t (%v)", valid, err)
+	}
+	if valid, _, err := oracle("bar", sig); err != nil || valid {
+		t.Fatalf("Expected invalid signature, but got %t (%v)", valid, err)
+	}
+
+	for _, size := range []int{0, sha1.Size + 1} {
+		if _, err := NewServer(key, size, 0); err != InvalidSizeErr {
+			t.Fatalf("Expected error %s for size %d, but got %v", InvalidSizeErr, size, err)
+		}
+	}
+}
+
+func TestRecoverSignature(t *testing.T) {
+	key := crypto.RandomKey(16)
+	size := 3
+	srv, err := NewServer(key, size, 2*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer srv.Close()
+
+	opts := AttackOpts{
+		Samples:   3,
+		MaxRounds: 3,
+	}
+	res, err := RecoverSignature(HttpOracle(srv.Client(), srv.URL), "foo", size, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := crypto.Hmac(sha1.New)(key, []byte("foo"))[:size]
+	if !bytes.Equal(expected, res.Signature) {
+		t.Fatalf("Expected signature %x, but got %x", expected, res.Signature)
+	}
+	if res.Queries < 256*(size-1) {
+		t.Fatalf("Expected at least %d queries, but got %d", 256*(size-1), res.Queries)
+	}
+
+	for _, size := range []int{0, sha1.Size + 1} {
+		if _, err := RecoverSignature(HttpOracle(srv.Client(), srv.URL), "foo", size, opts); err != InvalidSizeErr {
+			t.Fatalf("Expected error %s for size %d, but got %v", InvalidSizeErr, size, err)
+		}
+	}
+}
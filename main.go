@@ -1,12 +1,33 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/kdhageman/go-cryptopals/challenge/three/twentytwo"
+	"github.com/kdhageman/go-cryptopals/file"
+	"github.com/kdhageman/go-cryptopals/web"
 	"github.com/logrusorgru/aurora"
+	"net/http"
 )
 
 func main() {
+	serve := flag.String("serve", "", "serve the vulnerable web service on the given address, e.g. 127.0.0.1:8080")
+	flag.Parse()
+
+	if *serve != "" {
+		pts, err := file.ReadBase64Lines("challenge/three/seventeen/input.txt")
+		if err != nil {
+			fmt.Printf("Failed to read tokens: %s", aurora.Red(err.Error()))
+			return
+		}
+		h := web.NewHandler(pts)
+		fmt.Printf("Serving vulnerable web service on %s\n", aurora.Cyan(*serve))
+		if err := http.ListenAndServe(*serve, h); err != nil {
+			fmt.Printf("Failed to serve: %s", aurora.Red(err.Error()))
+		}
+		return
+	}
+
 	ch := twentytwo.New()
 	if err := ch.Solve(); err != nil {
 		fmt.Printf("Failed to solve challenge: %s", aurora.Red(err.Error()))
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge/three/seventeen"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+type UnexpectedStatusErr struct {
+	status int
+}
+
+func (err UnexpectedStatusErr) Error() string {
+	return fmt.Sprintf("unexpected status code %d", err.status)
+}
+
+// ShortTokenErr is returned when a token is too short to hold the IV
+type ShortTokenErr struct {
+	size int
+}
+
+func (err ShortTokenErr) Error() string {
+	return fmt.Sprintf("token of %d bytes is shorter than the IV", err.size)
+}
+
+// CookieOracle returns an oracle that posts its input as query parameter param to the endpoint,
+// and returns the cipher text in the cookie the server sets in response
+func CookieOracle(client *http.Client, endpoint string, param string, cookie string) crypto.Oracle {
+	return func(input []byte) ([]byte, error) {
+		q := url.Values{}
+		q.Set(param, string(input))
+		resp, err := client.Post(endpoint+"?"+q.Encode(), "", nil)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, UnexpectedStatusErr{resp.StatusCode}
+		}
+		for _, c := range resp.Cookies() {
+			if c.Name == cookie {
+				return hex.DecodeString(c.Value)
+			}
+		}
+		return nil, http.ErrNoCookie
+	}
+}
+
+// WithCookie requests the endpoint with the cipher text as cookie, and returns the status code and body of the response
+func WithCookie(client *http.Client, endpoint string, cookie string, ct []byte) (int, string, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.AddCookie(&http.Cookie{
+		Name:  cookie,
+		Value: hex.EncodeToString(ct),
+	})
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+func ProfileOracle(client *http.Client, base string) crypto.Oracle {
+	return CookieOracle(client, base+"/profile", "email", ProfileCookie)
+}
+
+func CommentsOracle(client *http.Client, base string) crypto.Oracle {
+	return CookieOracle(client, base+"/comments", "userdata", SessionCookie)
+}
+
+// TokenOracle fetches a token from the server
+func TokenOracle(client *http.Client, base string) seventeen.Encrypt {
+	return func() ([]byte, []byte, error) {
+		resp, err := client.Get(base + "/token")
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, UnexpectedStatusErr{resp.StatusCode}
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		token, err := hex.DecodeString(string(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(token) < 16 {
+			return nil, nil, ShortTokenErr{len(token)}
+		}
+		return token[16:], token[:16], nil
+	}
+}
+
+// PaddingOracle returns a padding oracle that submits cipher texts to the token check endpoint
+func PaddingOracle(client *http.Client, base string) seventeen.Decrypt {
+	return func(ct []byte) (bool, error) {
+		q := url.Values{}
+		q.Set("token", hex.EncodeToString(ct))
+		resp, err := client.Get(base + "/token/check?" + q.Encode())
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return true, nil
+		case http.StatusBadRequest:
+			return false, nil
+		}
+		return false, UnexpectedStatusErr{resp.StatusCode}
+	}
+}
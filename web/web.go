@@ -0,0 +1,144 @@
+// Package web hosts the oracles of challenges 13, 16 and 17 as a vulnerable web service, and provides clients
+// that turn its endpoints back into oracles, so the attacks can be practiced over the network.
+package web
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"fmt"
+	"github.com/kdhageman/go-cryptopals/challenge/three/seventeen"
+	"github.com/kdhageman/go-cryptopals/challenge/two/sixteen"
+	"github.com/kdhageman/go-cryptopals/challenge/two/thirteen"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"net/http"
+)
+
+const (
+	ProfileCookie = "profile"
+	SessionCookie = "session"
+)
+
+func setCookie(w http.ResponseWriter, name string, ct []byte) {
+	http.SetCookie(w, &http.Cookie{
+		Name:  name,
+		Value: hex.EncodeToString(ct),
+		Path:  "/",
+	})
+}
+
+func readCookie(r *http.Request, name string) ([]byte, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(c.Value)
+}
+
+// NewHandler returns a handler for the following endpoints, each using its own random key.
+// Tokens are encryptions of a random one of the given plain texts.
+//
+//	POST /profile?email=...       sets an ECB encrypted profile cookie (challenge 13)
+//	GET  /profile/role            returns the role in the profile cookie
+//	POST /comments?userdata=...   sets a CBC encrypted session cookie (challenge 16)
+//	GET  /admin                   returns 200 if the session cookie belongs to an admin, and 403 otherwise
+//	GET  /token                   returns a CBC encrypted token as hex encoded IV and cipher text (challenge 17)
+//	GET  /token/check?token=...   returns 200 if the token has valid padding, 400 if it has not,
+//	                              and 422 if the token is not hex encoded
+func NewHandler(pts [][]byte) http.Handler {
+	mux := http.NewServeMux()
+
+	profileEnc, profileDec := thirteen.Oracle(crypto.RandomKey(aes.BlockSize))
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		ct, err := profileEnc([]byte(r.URL.Query().Get("email")))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		setCookie(w, ProfileCookie, ct)
+	})
+	mux.HandleFunc("/profile/role", func(w http.ResponseWriter, r *http.Request) {
+		ct, err := readCookie(r, ProfileCookie)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pt, err := profileDec(ct)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if unpadded, err := crypto.RemovePkcs7(pt, aes.BlockSize); err == nil {
+			pt = unpadded
+		}
+		p, err := thirteen.Decode(string(pt))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, p["role"])
+	})
+
+	commentsEnc, commentsDec := sixteen.Oracle(crypto.RandomKey(aes.BlockSize), crypto.RandomKey(aes.BlockSize))
+	mux.HandleFunc("/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		ct, err := commentsEnc([]byte(r.URL.Query().Get("userdata")))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		setCookie(w, SessionCookie, ct)
+	})
+	mux.HandleFunc("/admin", func(w http.ResponseWriter, r *http.Request) {
+		ct, err := readCookie(r, SessionCookie)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pt, err := commentsDec(ct)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		u, err := sixteen.FromBytes(pt)
+		if err != nil || !u.IsAdmin() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "welcome, admin")
+	})
+
+	tokenEnc, tokenDec := seventeen.Oracle(pts, crypto.RandomKey(aes.BlockSize), crypto.RandomKey(aes.BlockSize))
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		ct, iv, err := tokenEnc()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, hex.EncodeToString(append(append([]byte{}, iv...), ct...)))
+	})
+	mux.HandleFunc("/token/check", func(w http.ResponseWriter, r *http.Request) {
+		ct, err := hex.DecodeString(r.URL.Query().Get("token"))
+		if err != nil {
+			// keep malformed requests apart from padding failures, which are reported with 400
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		valid, err := tokenDec(ct)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !valid {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	return mux
+}
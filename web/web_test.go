@@ -0,0 +1,130 @@
+package web
+
+import (
+	"bytes"
+	"crypto/aes"
+	"github.com/kdhageman/go-cryptopals/crypto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer() *httptest.Server {
+	pts := [][]byte{[]byte("000000Now that the party is jumping")}
+	return httptest.NewServer(NewHandler(pts))
+}
+
+func TestProfileCutAndPaste(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	tmpl := crypto.Template{
+		Prefix:    []byte("email="),
+		Suffix:    []byte("&uid=10&role=user"),
+		BlockSize: aes.BlockSize,
+		Forbidden: []byte("&="),
+	}
+	forged, err := crypto.ForgeCutAndPaste(ProfileOracle(srv.Client(), srv.URL), tmpl, []byte("email=foooo@bar.com&uid=10&role=admin"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	status, role, err := WithCookie(srv.Client(), srv.URL+"/profile/role", ProfileCookie, forged)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if status != http.StatusOK || role != "admin" {
+		t.Fatalf("Expected role %s, but got %s (%d)", "admin", role, status)
+	}
+}
+
+func TestCommentsBitflip(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	status, _, err := WithCookie(srv.Client(), srv.URL+"/admin", SessionCookie, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if status != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, but got %d", http.StatusBadRequest, status)
+	}
+
+	layout := crypto.Layout{
+		PrefixLen: len("comment1=cooking%20MCs;userdata="),
+		BlockSize: aes.BlockSize,
+		Forbidden: []byte(";="),
+	}
+	ct, _, err := crypto.CbcBitflipAttack(CommentsOracle(srv.Client(), srv.URL), layout, []byte(";admin=true"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	status, _, err = WithCookie(srv.Client(), srv.URL+"/admin", SessionCookie, ct)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, status)
+	}
+}
+
+func TestPaddingOracle(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	ct, iv, err := TokenOracle(srv.Client(), srv.URL)()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	pt, err := PaddingOracle(srv.Client(), srv.URL).DecryptBlock(ct[:aes.BlockSize], iv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []byte("000000Now that t"); !bytes.Equal(expected, pt) {
+		t.Fatalf("Expected plain text %q, but got %q", expected, pt)
+	}
+	resp, err := srv.Client().Get(srv.URL + "/token/check?token=not-hex")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status %d, but got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+}
+
+func TestTokenOracleErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{
+			name:     "Bad status",
+			status:   http.StatusInternalServerError,
+			body:     "",
+			expected: UnexpectedStatusErr{http.StatusInternalServerError},
+		},
+		{
+			name:     "Short token",
+			status:   http.StatusOK,
+			body:     "00112233",
+			expected: ShortTokenErr{4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			if _, _, err := TokenOracle(srv.Client(), srv.URL)(); err != tt.expected {
+				t.Fatalf("Expected error %v, but got %v", tt.expected, err)
+			}
+		})
+	}
+}